@@ -3,11 +3,14 @@ module github.com/gx-org/gx-org
 go 1.24.2
 
 require (
+	github.com/andybalholm/brotli v1.1.1
 	github.com/go-chi/chi/v5 v5.2.1
 	github.com/gomarkdown/markdown v0.0.0-20250311123330-531bef5e742b
 	github.com/google/go-cmp v0.6.0
 	github.com/gx-org/gx v0.0.0-20250609154441-6e8054fbb561
+	golang.org/x/net v0.41.0
 	golang.org/x/tools v0.34.0
+	gopkg.in/yaml.v3 v3.0.1
 	honnef.co/go/js/dom/v2 v2.0.0-20250304181735-b5e52f05e89d
 )
 
@@ -0,0 +1,351 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package assets serves the static files of the overview in production.
+//
+// Files in the res folder are given content-hashed URLs
+// (e.g. res/style.css is served as res/style.0123456789.css)
+// which can be cached forever by browsers. HTML pages at the root
+// are rewritten to use the hashed URLs and are revalidated on every visit.
+//
+// Resources are compressed with gzip and brotli ahead of time by Compress
+// (run by go generate) and the compressed files are served when they match
+// the content of the resource. HTML pages are small and are compressed
+// when the set is created.
+package assets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	// ResFolder is the folder of the resources with content-hashed URLs.
+	ResFolder = "res"
+	// IndexFile is the page served at the root.
+	IndexFile = "index.html"
+
+	hashLen = 10
+
+	// brotliLevel is lower than brotli.BestCompression
+	// which is too slow for the multi-megabyte WASM binary.
+	brotliLevel = 9
+
+	immutable  = "public, max-age=31536000, immutable"
+	revalidate = "no-cache"
+)
+
+// encoders in order of preference.
+var encoders = []struct {
+	name      string
+	ext       string
+	newWriter func(io.Writer) (io.WriteCloser, error)
+}{
+	{name: "br", ext: ".br", newWriter: newBrotliWriter},
+	{name: "gzip", ext: ".gz", newWriter: newGzipWriter},
+}
+
+type (
+	encoding struct {
+		name string
+		data []byte
+	}
+
+	file struct {
+		name         string
+		hash         string
+		cacheControl string
+		data         []byte
+		// encodings in order of preference.
+		encodings []encoding
+	}
+
+	// Set of assets served over HTTP.
+	Set struct {
+		files  map[string]*file
+		hashed map[string]string
+	}
+)
+
+// New returns a set of assets from the files in fsys.
+// fsys needs to contain index.html and the res folder.
+func New(fsys fs.FS) (*Set, error) {
+	s := &Set{
+		files:  make(map[string]*file),
+		hashed: make(map[string]string),
+	}
+	if err := fs.WalkDir(fsys, ResFolder, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if IsCompressed(name) {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		f := newFile(name, data, immutable)
+		if err := f.readEncodings(fsys); err != nil {
+			return err
+		}
+		hashedName := hashedName(name, f.hash)
+		s.hashed[name] = hashedName
+		s.files[hashedName] = f
+		// Also serve the file with its original name for pages that have not been rewritten.
+		original := *f
+		original.cacheControl = revalidate
+		s.files[name] = &original
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	pages, err := fs.Glob(fsys, "*.html")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range pages {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		f := newFile(name, s.rewrite(data), revalidate)
+		if err := f.compress(); err != nil {
+			return nil, err
+		}
+		s.files[name] = f
+	}
+	if s.files[IndexFile] == nil {
+		return nil, fmt.Errorf("%s not found", IndexFile)
+	}
+	return s, nil
+}
+
+func hashedName(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// URL returns the content-hashed URL of a file.
+// The name is returned unmodified if the file is not in the set.
+func (s *Set) URL(name string) string {
+	if hashed, ok := s.hashed[name]; ok {
+		return hashed
+	}
+	return name
+}
+
+// rewrite replaces all the quoted references to resources by their hashed URLs.
+func (s *Set) rewrite(page []byte) []byte {
+	for name, hashed := range s.hashed {
+		for _, quote := range []string{`"`, `'`} {
+			page = bytes.ReplaceAll(page, []byte(quote+name+quote), []byte(quote+hashed+quote))
+		}
+	}
+	return page
+}
+
+func compress(data []byte, newWriter func(io.Writer) (io.WriteCloser, error)) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := newWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func newBrotliWriter(w io.Writer) (io.WriteCloser, error) {
+	return brotli.NewWriterLevel(w, brotliLevel), nil
+}
+
+func newGzipWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, gzip.BestCompression)
+}
+
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:hashLen]
+}
+
+func newFile(name string, data []byte, cacheControl string) *file {
+	return &file{
+		name:         name,
+		hash:         hashOf(data),
+		cacheControl: cacheControl,
+		data:         data,
+	}
+}
+
+// addEncoding adds a compressed version of the file
+// unless it is not smaller than the original.
+func (f *file) addEncoding(name string, data []byte) {
+	if len(data) >= len(f.data) {
+		return
+	}
+	f.encodings = append(f.encodings, encoding{name: name, data: data})
+}
+
+// compress compresses the file with all the encoders.
+func (f *file) compress() error {
+	for _, enc := range encoders {
+		compressed, err := compress(f.data, enc.newWriter)
+		if err != nil {
+			return fmt.Errorf("cannot compress %s with %s: %v", f.name, enc.name, err)
+		}
+		f.addEncoding(enc.name, compressed)
+	}
+	return nil
+}
+
+// readEncodings reads the files written by Compress for the current content of the file.
+// Compressed files written for a previous content have a different name and are ignored.
+func (f *file) readEncodings(fsys fs.FS) error {
+	for _, enc := range encoders {
+		compressed, err := fs.ReadFile(fsys, hashedName(f.name, f.hash)+enc.ext)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		f.addEncoding(enc.name, compressed)
+	}
+	return nil
+}
+
+// IsCompressed returns true if a file has been written by Compress.
+func IsCompressed(name string) bool {
+	for _, enc := range encoders {
+		if strings.HasSuffix(name, enc.ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Compress writes the compressed versions of the files in the res folder of dir
+// and removes the compressed files of previous versions.
+// A compressed file is named after the hashed URL of the file it compresses
+// (e.g. res/style.0123456789.css.br) such that New ignores it once the file has changed.
+func Compress(dir string) error {
+	fsys := os.DirFS(dir)
+	var previous []string
+	written := make(map[string]bool)
+	if err := fs.WalkDir(fsys, ResFolder, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if IsCompressed(name) {
+			previous = append(previous, name)
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		for _, enc := range encoders {
+			compressed, err := compress(data, enc.newWriter)
+			if err != nil {
+				return fmt.Errorf("cannot compress %s with %s: %v", name, enc.name, err)
+			}
+			if len(compressed) >= len(data) {
+				continue
+			}
+			target := hashedName(name, hashOf(data)) + enc.ext
+			if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(target)), compressed, 0644); err != nil {
+				return err
+			}
+			written[target] = true
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, name := range previous {
+		if written[name] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accepts returns true if the Accept-Encoding header accepts an encoding.
+func accepts(header, enc string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.TrimSpace(name) != enc {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if key == "q" {
+				q, err := strconv.ParseFloat(value, 64)
+				return err == nil && q > 0
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// ServeHTTP serves a file of the set.
+func (s *Set) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	if name == "" {
+		name = IndexFile
+	}
+	f := s.files[name]
+	if f == nil {
+		http.NotFound(w, r)
+		return
+	}
+	h := w.Header()
+	h.Set("Cache-Control", f.cacheControl)
+	h.Add("Vary", "Accept-Encoding")
+	data, etag := f.data, f.hash
+	acceptEncoding := r.Header.Get("Accept-Encoding")
+	for _, enc := range f.encodings {
+		if accepts(acceptEncoding, enc.name) {
+			data, etag = enc.data, f.hash+"-"+enc.name
+			h.Set("Content-Encoding", enc.name)
+			break
+		}
+	}
+	h.Set("ETag", `"`+etag+`"`)
+	http.ServeContent(w, r, f.name, time.Time{}, bytes.NewReader(data))
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gx-org/gx-org/internal/assets"
+)
+
+var (
+	style = strings.Repeat("body { margin: 0px; }\n", 100)
+
+	testFS = fstest.MapFS{
+		"index.html":    {Data: []byte(`<link rel="stylesheet" href="res/style.css"/><script>load('res/main.wasm')</script>`)},
+		"res/style.css": {Data: []byte(style)},
+		"res/main.wasm": {Data: []byte("\x00asm")},
+	}
+)
+
+func get(t *testing.T, set *assets.Set, target string, header map[string]string) *http.Response {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	set.ServeHTTP(rec, req)
+	return rec.Result()
+}
+
+func body(t *testing.T, resp *http.Response) string {
+	var r io.Reader = resp.Body
+	switch resp.Header.Get("Content-Encoding") {
+	case "gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gz
+	case "br":
+		r = brotli.NewReader(resp.Body)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// compressed returns the set of assets of fsys after compressing them in a temporary folder.
+func compressed(t *testing.T, fsys fs.FS) (string, *assets.Set) {
+	dir := t.TempDir()
+	if err := os.CopyFS(dir, fsys); err != nil {
+		t.Fatal(err)
+	}
+	if err := assets.Compress(dir); err != nil {
+		t.Fatal(err)
+	}
+	set, err := assets.New(os.DirFS(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, set
+}
+
+func TestSet(t *testing.T) {
+	_, set := compressed(t, testFS)
+	styleURL := set.URL("res/style.css")
+	if styleURL == "res/style.css" || !strings.HasPrefix(styleURL, "res/style.") || !strings.HasSuffix(styleURL, ".css") {
+		t.Fatalf("unexpected hashed URL %q", styleURL)
+	}
+	wasmURL := set.URL("res/main.wasm")
+
+	index := get(t, set, "/", nil)
+	if got, want := body(t, index), `<link rel="stylesheet" href="`+styleURL+`"/><script>load('`+wasmURL+`')</script>`; got != want {
+		t.Errorf("unexpected index:\ngot:  %s\nwant: %s", got, want)
+	}
+	if got := index.Header.Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("index: unexpected Cache-Control %q", got)
+	}
+
+	tests := []struct {
+		acceptEncoding string
+		wantEncoding   string
+	}{
+		{acceptEncoding: "", wantEncoding: ""},
+		{acceptEncoding: "gzip", wantEncoding: "gzip"},
+		{acceptEncoding: "gzip, deflate, br", wantEncoding: "br"},
+		{acceptEncoding: "br;q=0, gzip;q=0.5", wantEncoding: "gzip"},
+	}
+	for i, test := range tests {
+		resp := get(t, set, "/"+styleURL, map[string]string{"Accept-Encoding": test.acceptEncoding})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("test %d: got status %d", i, resp.StatusCode)
+		}
+		if got := resp.Header.Get("Content-Encoding"); got != test.wantEncoding {
+			t.Errorf("test %d: got encoding %q but want %q", i, got, test.wantEncoding)
+		}
+		if got := resp.Header.Get("Cache-Control"); !strings.Contains(got, "immutable") {
+			t.Errorf("test %d: unexpected Cache-Control %q", i, got)
+		}
+		if got := body(t, resp); got != style {
+			t.Errorf("test %d: unexpected content %q", i, got)
+		}
+		etag := resp.Header.Get("ETag")
+		cached := get(t, set, "/"+styleURL, map[string]string{
+			"Accept-Encoding": test.acceptEncoding,
+			"If-None-Match":   etag,
+		})
+		if cached.StatusCode != http.StatusNotModified {
+			t.Errorf("test %d: got status %d with If-None-Match %s but want %d", i, cached.StatusCode, etag, http.StatusNotModified)
+		}
+	}
+
+	// Compressed files larger than the original are not used.
+	wasm := get(t, set, "/"+wasmURL, map[string]string{"Accept-Encoding": "gzip"})
+	if got := wasm.Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("unexpected encoding %q for a small file", got)
+	}
+	if got := get(t, set, "/res/unknown.js", nil).StatusCode; got != http.StatusNotFound {
+		t.Errorf("got status %d for an unknown file", got)
+	}
+	if got := body(t, get(t, set, "/res/style.css", nil)); !bytes.Equal([]byte(got), []byte(style)) {
+		t.Errorf("cannot get a file with its original name")
+	}
+}
+
+func TestCompressChangedFile(t *testing.T) {
+	dir, set := compressed(t, testFS)
+	oldURL := set.URL("res/style.css")
+	newStyle := style + "p { margin: 0px; }\n"
+	if err := os.WriteFile(filepath.Join(dir, "res", "style.css"), []byte(newStyle), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The files compressed for the previous content are not served.
+	set, err := assets.New(os.DirFS(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := get(t, set, "/"+set.URL("res/style.css"), map[string]string{"Accept-Encoding": "br"})
+	if got := resp.Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("got encoding %q for a file without compressed files", got)
+	}
+	if got := body(t, resp); got != newStyle {
+		t.Errorf("unexpected content %q", got)
+	}
+	// Compressing again replaces the previous compressed files.
+	if err := assets.Compress(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, oldURL+".br")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("compressed file %s of the previous content has not been removed: %v", oldURL+".br", err)
+	}
+	if set, err = assets.New(os.DirFS(dir)); err != nil {
+		t.Fatal(err)
+	}
+	resp = get(t, set, "/"+set.URL("res/style.css"), map[string]string{"Accept-Encoding": "br"})
+	if got := resp.Header.Get("Content-Encoding"); got != "br" {
+		t.Errorf("got encoding %q but want br", got)
+	}
+	if got := body(t, resp); got != newStyle {
+		t.Errorf("unexpected content %q", got)
+	}
+}
+
+func TestNewLargeFile(t *testing.T) {
+	// The size of the WASM binary: compressing it with brotli takes several seconds.
+	wasm := make([]byte, 16<<20)
+	rand.New(rand.NewSource(1)).Read(wasm)
+	fsys := fstest.MapFS{
+		"index.html":    testFS["index.html"],
+		"res/main.wasm": {Data: wasm},
+	}
+	start := time.Now()
+	if _, err := assets.New(fsys); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("creating a set with a %d MiB file took %s: files should not be compressed when the set is created", len(wasm)>>20, d)
+	}
+}
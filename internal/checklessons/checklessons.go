@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command checklessons compiles and runs the code of all the lessons
+// on the Go backend and reports the lessons failing.
+//
+// The output of a lesson is compared with its overview:output block if any.
+// With --update, overview:output blocks are rewritten with the current output
+// instead.
+//
+// Usage:
+//
+//	go run ./internal/checklessons [--update]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gx-org/gx-org/internal/lessoncheck"
+	"github.com/gx-org/gx-org/internal/lessons"
+	"github.com/gx-org/gx-org/internal/mdtext"
+	"github.com/gx-org/gx-org/internal/project"
+)
+
+var update = flag.Bool("update", false, "rewrite the expected output of lessons with their current output")
+
+const lessonsFolder = "lessons"
+
+func updateOutput(projectRoot string, les *lessons.Lesson, output string) error {
+	path := filepath.Join(projectRoot, lessonsFolder, les.File)
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	src, err = mdtext.ReplaceCode(src, mdtext.OutputTag, output)
+	if err != nil {
+		return fmt.Errorf("%s: %v", les.File, err)
+	}
+	return os.WriteFile(path, src, 0644)
+}
+
+func checkLesson(projectRoot string, les *lessons.Lesson) error {
+	if !*update || les.Output == "" {
+		return lessoncheck.Lesson(les)
+	}
+	output, err := lessoncheck.Run(les)
+	if err != nil {
+		return err
+	}
+	return updateOutput(projectRoot, les, output)
+}
+
+func check() error {
+	projectRoot, err := project.Root()
+	if err != nil {
+		return err
+	}
+	chapters, err := lessons.New()
+	if err != nil {
+		return err
+	}
+	numFailures := 0
+	for _, chap := range chapters {
+		for _, les := range chap.Content {
+			start := time.Now()
+			if err := checkLesson(projectRoot, les); err != nil {
+				numFailures++
+				fmt.Printf("FAIL %s\n%v\n", les.File, err)
+				continue
+			}
+			fmt.Printf("ok   %s (%s)\n", les.File, time.Since(start).Round(time.Millisecond))
+		}
+	}
+	if numFailures > 0 {
+		return fmt.Errorf("%d lesson(s) failed", numFailures)
+	}
+	return nil
+}
+
+func main() {
+	flag.Parse()
+	if err := check(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
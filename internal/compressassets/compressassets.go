@@ -0,0 +1,44 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command compressassets writes the compressed versions of the resources
+// served in production. It is run by go generate after the WASM binary is built.
+//
+// Usage:
+//
+//	go run ./internal/compressassets
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gx-org/gx-org/internal/assets"
+	"github.com/gx-org/gx-org/internal/project"
+)
+
+func compress() error {
+	projectRoot, err := project.Root()
+	if err != nil {
+		return err
+	}
+	return assets.Compress(projectRoot)
+}
+
+func main() {
+	if err := compress(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diag converts GX compiler errors into structured diagnostics.
+package diag
+
+import (
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SeverityError is the severity of diagnostics preventing the code from compiling.
+const SeverityError = "error"
+
+// Diagnostic is a problem found in a source file.
+// Lines and columns start at 1. Like Go, columns count bytes.
+// The end position is exclusive. Positions are 0 when unknown.
+type Diagnostic struct {
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+}
+
+// posRegexp matches errors of the form file:line:column: message
+// where the file is optional.
+var posRegexp = regexp.MustCompile(`^(?:(.*?):)?(\d+):(\d+):\s*(.*)$`)
+
+// FromError returns the diagnostics of a compiler error.
+// Errors joined with errors.Join and errors spanning several lines,
+// each starting with a position, are split into several diagnostics.
+// srcs are the sources of the compiled files given their name. The source of the file
+// of a diagnostic is used to compute its end position, which covers the token
+// at the error position. The end position is the error position if the source is unknown.
+func FromError(err error, srcs map[string]string) []Diagnostic {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var diags []Diagnostic
+		for _, err := range joined.Unwrap() {
+			diags = append(diags, FromError(err, srcs)...)
+		}
+		return diags
+	}
+	return fromMessage(err.Error(), srcs)
+}
+
+func fromMessage(msg string, srcs map[string]string) []Diagnostic {
+	var diags []Diagnostic
+	for _, line := range strings.Split(msg, "\n") {
+		match := posRegexp.FindStringSubmatch(line)
+		if match == nil {
+			if len(diags) > 0 {
+				// Continuation of the previous message.
+				last := &diags[len(diags)-1]
+				last.Message += "\n" + line
+				continue
+			}
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			diags = append(diags, Diagnostic{Severity: SeverityError, Message: line})
+			continue
+		}
+		lineNum, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+		diag := Diagnostic{
+			File:     match[1],
+			Line:     lineNum,
+			Column:   column,
+			Severity: SeverityError,
+			Message:  match[4],
+		}
+		diag.EndLine, diag.EndColumn = lineNum, column
+		if src, ok := srcs[diag.File]; ok {
+			diag.EndLine, diag.EndColumn = tokenEnd(src, lineNum, column)
+		}
+		diags = append(diags, diag)
+	}
+	return diags
+}
+
+// tokenEnd returns the position after the token starting at line and column.
+// GX shares the lexical syntax of Go, so the source is split into tokens by the Go scanner.
+// The position is returned unchanged if no token starts at line and column.
+func tokenEnd(src string, line, column int) (int, int) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", -1, len(src))
+	var s scanner.Scanner
+	s.Init(file, []byte(src), nil, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return line, column
+		}
+		start := fset.Position(pos)
+		if start.Line > line || (start.Line == line && start.Column > column) {
+			return line, column
+		}
+		if start.Line != line || start.Column != column {
+			continue
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			// Semicolon inserted at the end of the line.
+			return line, column
+		}
+		if lit == "" {
+			lit = tok.String()
+		}
+		end := fset.Position(pos + token.Pos(len(lit)))
+		return end.Line, end.Column
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diag_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gx-org/gx-org/internal/diag"
+)
+
+var srcs = map[string]string{
+	"main.gx": `package main
+
+func Main() float32 {
+	return undefinedVar + 1
+}
+`,
+	"util.gx": `package main
+
+func Util() float32 {
+	return helperValue
+}
+`,
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want []diag.Diagnostic
+	}{
+		{err: nil},
+		{
+			err: errors.New("main.gx:4:9: undefined: undefinedVar"),
+			want: []diag.Diagnostic{{
+				File: "main.gx", Line: 4, Column: 9, EndLine: 4, EndColumn: 21,
+				Severity: diag.SeverityError, Message: "undefined: undefinedVar",
+			}},
+		},
+		{
+			err: errors.Join(
+				errors.New("4:22: invalid operation\n\tmore details"),
+				errors.New("cannot build package"),
+			),
+			want: []diag.Diagnostic{
+				{
+					// The file, and so the end of the token, is unknown.
+					Line: 4, Column: 22, EndLine: 4, EndColumn: 22,
+					Severity: diag.SeverityError, Message: "invalid operation\n\tmore details",
+				},
+				{Severity: diag.SeverityError, Message: "cannot build package"},
+			},
+		},
+		{
+			err: errors.New("main.gx:3:6: first\nmain.gx:100:1: out of the source"),
+			want: []diag.Diagnostic{
+				{
+					File: "main.gx", Line: 3, Column: 6, EndLine: 3, EndColumn: 10,
+					Severity: diag.SeverityError, Message: "first",
+				},
+				{
+					File: "main.gx", Line: 100, Column: 1, EndLine: 100, EndColumn: 1,
+					Severity: diag.SeverityError, Message: "out of the source",
+				},
+			},
+		},
+		{
+			err: errors.Join(
+				errors.New("util.gx:4:9: undefined: helperValue"),
+				errors.New("main.gx:4:9: undefined: undefinedVar"),
+				errors.New("other.gx:4:9: unknown file"),
+			),
+			want: []diag.Diagnostic{
+				{
+					File: "util.gx", Line: 4, Column: 9, EndLine: 4, EndColumn: 20,
+					Severity: diag.SeverityError, Message: "undefined: helperValue",
+				},
+				{
+					File: "main.gx", Line: 4, Column: 9, EndLine: 4, EndColumn: 21,
+					Severity: diag.SeverityError, Message: "undefined: undefinedVar",
+				},
+				{
+					File: "other.gx", Line: 4, Column: 9, EndLine: 4, EndColumn: 9,
+					Severity: diag.SeverityError, Message: "unknown file",
+				},
+			},
+		},
+	}
+	for i, test := range tests {
+		got := diag.FromError(test.err, srcs)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("test %d: unexpected diagnostics (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestEndPosition(t *testing.T) {
+	const src = "package main\n\nfunc Main() float32 {\n\tx := 1.5e3 // comment\n\ts := `a\nb`\n\treturn x\n}\n"
+	tests := []struct {
+		pos                string
+		endLine, endColumn int
+	}{
+		{pos: "4:2", endLine: 4, endColumn: 3},
+		{pos: "4:4", endLine: 4, endColumn: 6},
+		{pos: "4:7", endLine: 4, endColumn: 12},
+		{pos: "4:13", endLine: 4, endColumn: 23},
+		{pos: "5:7", endLine: 6, endColumn: 3},
+		{pos: "6:10", endLine: 6, endColumn: 10},
+		{pos: "4:8", endLine: 4, endColumn: 8},
+	}
+	for _, test := range tests {
+		got := diag.FromError(errors.New("main.gx:"+test.pos+": error"), map[string]string{"main.gx": src})
+		if len(got) != 1 {
+			t.Fatalf("%s: got %d diagnostics but want 1", test.pos, len(got))
+		}
+		if got[0].EndLine != test.endLine || got[0].EndColumn != test.endColumn {
+			t.Errorf("%s: got end position %d:%d but want %d:%d", test.pos, got[0].EndLine, got[0].EndColumn, test.endLine, test.endColumn)
+		}
+	}
+}
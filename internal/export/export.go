@@ -0,0 +1,242 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command export writes the overview as a static website.
+//
+// The output folder contains index.html, the res folder (including main.wasm),
+// and one HTML page per lesson named after the slug of the lesson. Lesson pages
+// include the lesson text for readers without JavaScript. Pages named after
+// the chapter and lesson numbers (e.g. 2_1.html) redirect to the lesson pages
+// for links created before lessons had slugs. The folder can be uploaded as-is
+// to any static hosting service.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gx-org/gx-org/internal/assets"
+	"github.com/gx-org/gx-org/internal/lessons"
+	"github.com/gx-org/gx-org/internal/project"
+	"github.com/gx-org/gx-org/internal/wasmbuild"
+)
+
+var out = flag.String("out", "", "output folder (must not exist or be empty)")
+
+const (
+	indexFile = "index.html"
+	resFolder = "res"
+	wasmFile  = "main.wasm"
+
+	// bodyTag is the tag in index.html in which the lesson is inserted.
+	bodyTag = `<body class="root_container">`
+)
+
+var lessonTmpl = template.Must(template.New("lesson").Funcs(template.FuncMap{
+	"pageName": pageName,
+}).Parse(`<body class="root_container" data-lesson="{{.Slug}}">
+<noscript>
+<div class="lesson_container">
+<div class="lesson_content">
+{{.Content}}
+{{- range .Sources}}
+{{- if gt (len $.Sources) 1}}
+<p><code>{{.Name}}</code></p>
+{{- end}}
+<pre><code>{{.Source}}</code></pre>
+{{- end}}
+</div>
+<div class="lesson_navigation">
+{{- if .Prev}}
+<a class="navigation_button" href="{{pageName .Prev}}">←</a>
+{{- end}}
+<p>Chapter {{.Chapter.ID}}, lesson {{.ID}}/{{.Chapter.NumLessons}}</p>
+{{- if .Next}}
+<a class="navigation_button" href="{{pageName .Next}}">→</a>
+{{- end}}
+</div>
+</div>
+</noscript>`))
+
+var redirectTmpl = template.Must(template.New("redirect").Funcs(template.FuncMap{
+	"pageName": pageName,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="0; url={{pageName .}}">
+<link rel="canonical" href="{{pageName .}}">
+</head>
+<body>
+<a href="{{pageName .}}">{{.Slug}}</a>
+</body>
+</html>
+`))
+
+func pageName(les *lessons.Lesson) string {
+	return les.Slug + ".html"
+}
+
+// numericPageName returns the name of the page of a lesson before lessons had slugs.
+func numericPageName(les *lessons.Lesson) string {
+	return fmt.Sprintf("%d_%d.html", les.Chapter.ID, les.ID)
+}
+
+type lessonPage struct {
+	*lessons.Lesson
+	Content template.HTML
+}
+
+func checkOutput(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return os.MkdirAll(dir, 0755)
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("output folder %s is not empty", dir)
+	}
+	return nil
+}
+
+func copyFile(dst, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	w, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func copyResources(projectRoot string) error {
+	resRoot := filepath.Join(projectRoot, resFolder)
+	return filepath.WalkDir(resRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(projectRoot, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(*out, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if rel == filepath.Join(resFolder, wasmFile) {
+			// The WASM binary is always built from source.
+			return nil
+		}
+		if assets.IsCompressed(rel) {
+			// Compressed files are only served by the server.
+			return nil
+		}
+		return copyFile(target, path)
+	})
+}
+
+func writeLesson(index string, les *lessons.Lesson) error {
+	var body bytes.Buffer
+	if err := lessonTmpl.Execute(&body, lessonPage{
+		Lesson:  les,
+		Content: template.HTML(les.HTML),
+	}); err != nil {
+		return err
+	}
+	page := strings.Replace(index, bodyTag, body.String(), 1)
+	if err := os.WriteFile(filepath.Join(*out, pageName(les)), []byte(page), 0644); err != nil {
+		return err
+	}
+	if numericPageName(les) == pageName(les) {
+		return nil
+	}
+	var redirect bytes.Buffer
+	if err := redirectTmpl.Execute(&redirect, les); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(*out, numericPageName(les)), redirect.Bytes(), 0644)
+}
+
+func writeLessons(projectRoot string) error {
+	index, err := os.ReadFile(filepath.Join(projectRoot, indexFile))
+	if err != nil {
+		return err
+	}
+	if !bytes.Contains(index, []byte(bodyTag)) {
+		return fmt.Errorf("%s: cannot find %s", indexFile, bodyTag)
+	}
+	chapters, err := lessons.New()
+	if err != nil {
+		return err
+	}
+	for _, chap := range chapters {
+		for _, les := range chap.Content {
+			if err := writeLesson(string(index), les); err != nil {
+				return fmt.Errorf("cannot write chapter %d lesson %d: %v", chap.ID, les.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func export() error {
+	if *out == "" {
+		return fmt.Errorf("no output folder specified: use --out")
+	}
+	if err := checkOutput(*out); err != nil {
+		return err
+	}
+	projectRoot, err := project.Root()
+	if err != nil {
+		return err
+	}
+	moduleRoot, err := project.ModuleRoot()
+	if err != nil {
+		return err
+	}
+	if err := copyResources(projectRoot); err != nil {
+		return err
+	}
+	if err := copyFile(filepath.Join(*out, indexFile), filepath.Join(projectRoot, indexFile)); err != nil {
+		return err
+	}
+	if err := writeLessons(projectRoot); err != nil {
+		return err
+	}
+	return wasmbuild.Build(moduleRoot, filepath.Join(*out, resFolder, wasmFile))
+}
+
+func main() {
+	flag.Parse()
+	if err := export(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gx-org/gx-org/internal/lessons"
+	"github.com/gx-org/gx-org/internal/project"
+)
+
+func TestWriteLessons(t *testing.T) {
+	projectRoot, err := project.Root()
+	if err != nil {
+		t.Fatal(err)
+	}
+	*out = t.TempDir()
+	if err := writeLessons(projectRoot); err != nil {
+		t.Fatal(err)
+	}
+	chapters, err := lessons.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chap := range chapters {
+		for _, les := range chap.Content {
+			page, err := os.ReadFile(filepath.Join(*out, pageName(les)))
+			if err != nil {
+				t.Errorf("lesson %s: %v", les.File, err)
+				continue
+			}
+			if !strings.Contains(string(page), `data-lesson="`+les.Slug+`"`) {
+				t.Errorf("page %s does not set the lesson %s", pageName(les), les.Slug)
+			}
+			if !strings.Contains(string(page), les.HTML) {
+				t.Errorf("page %s does not contain the text of lesson %s", pageName(les), les.File)
+			}
+			redirect, err := os.ReadFile(filepath.Join(*out, numericPageName(les)))
+			if err != nil {
+				t.Errorf("lesson %s: %v", les.File, err)
+				continue
+			}
+			if !strings.Contains(string(redirect), `url=`+pageName(les)+`"`) {
+				t.Errorf("page %s does not redirect to %s:\n%s", numericPageName(les), pageName(les), redirect)
+			}
+		}
+	}
+}
@@ -15,3 +15,4 @@
 package generate
 
 //go:generate bash -c "GOOS=js GOARCH=wasm go build -o ../../res/main.wasm ../wasm/wasm.go"
+//go:generate go run ../compressassets
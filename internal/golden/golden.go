@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package golden compares the output of GX code with an expected output.
+//
+// Outputs are split into tokens. Numbers match if they are equal
+// up to a tolerance, such that the output of floating-point computations
+// does not depend on the backend. Other tokens need to be equal.
+// Whitespace is ignored.
+package golden
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// RelTolerance is the relative tolerance when comparing numbers.
+	RelTolerance = 1e-5
+	// AbsTolerance is the absolute tolerance when comparing numbers.
+	AbsTolerance = 1e-6
+)
+
+var tokenRegexp = regexp.MustCompile(`[\pL_][\pL\pN_]*|[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?|\S`)
+
+func tokenize(s string) []string {
+	return tokenRegexp.FindAllString(s, -1)
+}
+
+func closeEnough(got, want float64) bool {
+	if math.IsNaN(got) || math.IsNaN(want) {
+		return math.IsNaN(got) && math.IsNaN(want)
+	}
+	if got == want {
+		return true
+	}
+	return math.Abs(got-want) <= AbsTolerance+RelTolerance*math.Abs(want)
+}
+
+func tokenMatch(got, want string) bool {
+	if got == want {
+		return true
+	}
+	gotF, gotErr := strconv.ParseFloat(got, 64)
+	wantF, wantErr := strconv.ParseFloat(want, 64)
+	if gotErr != nil || wantErr != nil {
+		return false
+	}
+	return closeEnough(gotF, wantF)
+}
+
+// Match returns an error describing the first difference between got and want.
+// It returns nil if the outputs match.
+func Match(got, want string) error {
+	gotT, wantT := tokenize(got), tokenize(want)
+	for i := 0; i < min(len(gotT), len(wantT)); i++ {
+		if !tokenMatch(gotT[i], wantT[i]) {
+			return fmt.Errorf("token %d: got %s but want %s", i+1, gotT[i], wantT[i])
+		}
+	}
+	if len(gotT) > len(wantT) {
+		return fmt.Errorf("unexpected %s at the end of the output", gotT[len(wantT)])
+	}
+	if len(gotT) < len(wantT) {
+		return fmt.Errorf("output ends before expected %s", wantT[len(gotT)])
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golden_test
+
+import (
+	"testing"
+
+	"github.com/gx-org/gx-org/internal/golden"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		got, want string
+		ok        bool
+	}{
+		{got: "", want: "", ok: true},
+		{got: "[1 2]", want: "[1 2]\n", ok: true},
+		{got: "[1  2]", want: "[ 1 2 ]", ok: true},
+		{got: "[0.33333334 1e-07]", want: "[0.333333 0]", ok: true},
+		{got: "[1000000.1]", want: "[1e6]", ok: true},
+		{got: "[NaN +Inf]", want: "[NaN +Inf]", ok: true},
+		{got: "0: [1 2]\n1: 3\n", want: "0: [1 2]\n1: 3", ok: true},
+		{got: "[1.01 2]", want: "[1 2]", ok: false},
+		{got: "[1 2]", want: "[1 2 3]", ok: false},
+		{got: "[1 2 3]", want: "[1 2]", ok: false},
+		{got: "[NaN]", want: "[0]", ok: false},
+		{got: "float32", want: "float64", ok: false},
+		{got: "[1 2]", want: "(1 2)", ok: false},
+	}
+	for i, test := range tests {
+		err := golden.Match(test.got, test.want)
+		if ok := err == nil; ok != test.ok {
+			t.Errorf("test %d: Match(%q, %q) returned error %v but want ok=%v", i, test.got, test.want, err, test.ok)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gxlit decodes values written as GX or JSON literals given their GX type.
+package gxlit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Value is a literal decoded given a GX type.
+type Value struct {
+	// DType is the data type of the elements of the value (e.g. float32).
+	DType string
+	// Dims are the lengths of the axes of the value. Dims is empty for a scalar.
+	Dims []int
+	// Elements of the value in row-major order. Only the slice matching DType is set:
+	// Floats for floating-point types, Ints for signed and unsigned integers, and Bools for bool.
+	Floats []float64
+	Ints   []int64
+	Bools  []bool
+}
+
+var typeRegexp = regexp.MustCompile(`^((?:\[\d+\])*)(bool|int32|int64|uint32|uint64|float32|float64)$`)
+
+var dimRegexp = regexp.MustCompile(`\d+`)
+
+var trailingCommaRegexp = regexp.MustCompile(`,\s*}`)
+
+// fromGX converts a GX array literal (e.g. [2]float32{1, 2}) to JSON.
+// Other values are returned unchanged.
+func fromGX(typ, value string) (string, error) {
+	open := strings.IndexByte(value, '{')
+	if open < 0 || !strings.HasSuffix(value, "}") {
+		return value, nil
+	}
+	if litType := strings.TrimSpace(value[:open]); litType != typ {
+		return "", fmt.Errorf("literal of type %q but want %s", litType, typ)
+	}
+	body := trailingCommaRegexp.ReplaceAllString(value[open:], "}")
+	return strings.NewReplacer("{", "[", "}", "]").Replace(body), nil
+}
+
+// Parse decodes a number, a boolean, or an array of those given a GX type.
+// Arrays are written as GX array literals or as JSON arrays.
+// Only builtin types and arrays of builtin types are supported.
+// For example, [2][2]float32{{1, 2}, {3, 4}} or [[1, 2], [3, 4]] of type [2][2]float32
+// is decoded to a value with the dimensions [2, 2] and the elements [1, 2, 3, 4].
+func Parse(typ, value string) (*Value, error) {
+	match := typeRegexp.FindStringSubmatch(typ)
+	if match == nil {
+		return nil, fmt.Errorf("unsupported type %s: only booleans, numbers, and arrays of those are supported", typ)
+	}
+	val := &Value{DType: match[2]}
+	for _, dim := range dimRegexp.FindAllString(match[1], -1) {
+		n, err := strconv.Atoi(dim)
+		if err != nil {
+			return nil, fmt.Errorf("invalid axis length %s in type %s: %v", dim, typ, err)
+		}
+		val.Dims = append(val.Dims, n)
+	}
+	value = strings.TrimSpace(value)
+	js, err := fromGX(typ, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %s: %v", value, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader([]byte(js)))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid value %s: %v", value, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid value %s: unexpected data after the value", value)
+	}
+	if err := val.decode(v, val.Dims); err != nil {
+		return nil, fmt.Errorf("cannot decode %s as %s: %v", value, typ, err)
+	}
+	return val, nil
+}
+
+func (val *Value) decode(v any, dims []int) error {
+	if len(dims) > 0 {
+		elements, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("got %v but want an array of length %d", v, dims[0])
+		}
+		if len(elements) != dims[0] {
+			return fmt.Errorf("got an array of length %d but want %d", len(elements), dims[0])
+		}
+		for _, el := range elements {
+			if err := val.decode(el, dims[1:]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	switch val.DType {
+	case "bool":
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("got %v but want a boolean", v)
+		}
+		val.Bools = append(val.Bools, b)
+	case "float32", "float64":
+		num, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("got %v but want a number", v)
+		}
+		f, err := num.Float64()
+		if err != nil {
+			return err
+		}
+		val.Floats = append(val.Floats, f)
+	default:
+		num, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("got %v but want an integer", v)
+		}
+		i, err := strconv.ParseInt(num.String(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("got %v but want an integer", num)
+		}
+		if err := checkRange(val.DType, i); err != nil {
+			return err
+		}
+		val.Ints = append(val.Ints, i)
+	}
+	return nil
+}
+
+func checkRange(dtype string, i int64) error {
+	var lo, hi int64
+	switch dtype {
+	case "int32":
+		lo, hi = math.MinInt32, math.MaxInt32
+	case "uint32":
+		lo, hi = 0, math.MaxUint32
+	case "uint64":
+		lo, hi = 0, math.MaxInt64
+	default:
+		return nil
+	}
+	if i < lo || i > hi {
+		return fmt.Errorf("%d overflows %s", i, dtype)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gxlit_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gx-org/gx-org/internal/gxlit"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		typ, value string
+		want       *gxlit.Value
+		err        bool
+	}{
+		{typ: "float32", value: "3", want: &gxlit.Value{DType: "float32", Floats: []float64{3}}},
+		{typ: "float64", value: " -1.5e-3\n", want: &gxlit.Value{DType: "float64", Floats: []float64{-1.5e-3}}},
+		{typ: "bool", value: "true", want: &gxlit.Value{DType: "bool", Bools: []bool{true}}},
+		{typ: "[3]int32", value: "[1, 2, 3]", want: &gxlit.Value{DType: "int32", Dims: []int{3}, Ints: []int64{1, 2, 3}}},
+		{
+			typ:   "[2][2]float32",
+			value: "[[1, 2], [3, 4]]",
+			want:  &gxlit.Value{DType: "float32", Dims: []int{2, 2}, Floats: []float64{1, 2, 3, 4}},
+		},
+		{typ: "[0]float32", value: "[]", want: &gxlit.Value{DType: "float32", Dims: []int{0}}},
+		{typ: "[2]float32", value: "[2]float32{1, 2}", want: &gxlit.Value{DType: "float32", Dims: []int{2}, Floats: []float64{1, 2}}},
+		{
+			typ:   "[2][2]int64",
+			value: "[2][2]int64{\n\t{1, 2},\n\t{3, 4},\n}",
+			want:  &gxlit.Value{DType: "int64", Dims: []int{2, 2}, Ints: []int64{1, 2, 3, 4}},
+		},
+		{typ: "[2]float32", value: "[2]float64{1, 2}", err: true},
+		{typ: "[2]float32", value: "[2]float32{1, 2, 3}", err: true},
+		{typ: "[2]float32", value: "[2]float32{1 2}", err: true},
+		{typ: "[2]float32", value: "[1, 2, 3]", err: true},
+		{typ: "[2][2]float32", value: "[1, 2]", err: true},
+		{typ: "int32", value: "1.5", err: true},
+		{typ: "int32", value: "3000000000", err: true},
+		{typ: "uint32", value: "-1", err: true},
+		{typ: "bool", value: "1", err: true},
+		{typ: "float32", value: "1 2", err: true},
+		{typ: "string", value: `"text"`, err: true},
+		{typ: "pkg.Type", value: "1", err: true},
+		{typ: "[1]float32", value: "[null]", err: true},
+	}
+	for i, test := range tests {
+		got, err := gxlit.Parse(test.typ, test.value)
+		if test.err {
+			if err == nil {
+				t.Errorf("test %d: expected an error but got %v", i, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: %v", i, err)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("test %d: unexpected value (-want +got):\n%s", i, diff)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gxrun compiles and runs GX code on the Go native backend.
+package gxrun
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gx-org/gx-org/internal/gxlit"
+	"github.com/gx-org/gx/api"
+	"github.com/gx-org/gx/api/tracer"
+	"github.com/gx-org/gx/api/values"
+	"github.com/gx-org/gx/build/builder"
+	"github.com/gx-org/gx/build/importers"
+	"github.com/gx-org/gx/build/importers/fsimporter"
+	"github.com/gx-org/gx/build/ir"
+	"github.com/gx-org/gx/golang/backend"
+	"github.com/gx-org/gx/golang/backend/kernels"
+	"github.com/gx-org/gx/stdlib"
+)
+
+const (
+	// PackageName is the name of the package in which the code is compiled.
+	PackageName = "main"
+	// TestPrefix is the prefix of the name of test functions.
+	TestPrefix = "Test"
+)
+
+type (
+	// Runner compiles and runs GX code.
+	Runner struct {
+		bld    *builder.Builder
+		dev    *api.Device
+		devErr error
+	}
+
+	// File is a named GX source file.
+	File struct {
+		Name   string
+		Source string
+	}
+
+	// Args are the arguments passed to a function when a package is run.
+	Args struct {
+		// Func is the name of the function. The arguments are passed to the first
+		// function if Func is empty.
+		Func string
+		// Values of the arguments as GX or JSON literals.
+		Values []string
+	}
+
+	// fileError is an error compiling a file.
+	fileError struct {
+		name string
+		err  error
+	}
+
+	// Package is a compiled GX package.
+	Package struct {
+		// IR is the intermediate representation of the package.
+		IR *ir.Package
+		// tests are the names of the test functions of the package.
+		tests map[string]bool
+	}
+
+	// Result of calling a function.
+	Result struct {
+		// Name of the function.
+		Name string
+		// Values returned by the function.
+		Values []values.Value
+		// Output is a string representation of the values.
+		Output string
+		// Err is the error returned when calling the function.
+		Err error
+		// Duration is the time it took to trace and run the function.
+		Duration time.Duration
+	}
+)
+
+// New returns a runner importing packages from the GX standard library
+// and from the file systems of packages, in order. The path of a package
+// in a file system is its import path.
+func New(packages ...fs.FS) *Runner {
+	imps := []importers.Importer{stdlib.Importer(nil)}
+	for _, pkgs := range packages {
+		imps = append(imps, fsimporter.New(pkgs))
+	}
+	bld := builder.New(importers.NewCacheLoader(imps...))
+	r := &Runner{bld: bld}
+	r.dev, r.devErr = backend.New(bld).Device(0)
+	return r
+}
+
+// posRegexp matches the position at the start of an error line
+// with an optional file name.
+var posRegexp = regexp.MustCompile(`^(?:[^\s:]*:)?(\d+:\d+:)`)
+
+// Error returns the error message in which positions refer to the file.
+func (e *fileError) Error() string {
+	lines := strings.Split(e.err.Error(), "\n")
+	for i, line := range lines {
+		lines[i] = posRegexp.ReplaceAllString(line, e.name+":$1")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *fileError) Unwrap() error {
+	return e.err
+}
+
+// Compile GX files into a package.
+// Files are built in order into the same package. Empty files are skipped.
+// Positions in compile errors are prefixed with the name of the file.
+func (r *Runner) Compile(files ...File) (*Package, error) {
+	return r.CompileWithTests(files, nil)
+}
+
+// CompileWithTests compiles GX files followed by test files into a package.
+// Exported functions declared in the test files with a name starting with TestPrefix
+// are the test functions of the package.
+func (r *Runner) CompileWithTests(files, tests []File) (*Package, error) {
+	if r.devErr != nil {
+		return nil, fmt.Errorf("Cannot initialise backend: %s", r.devErr.Error())
+	}
+	pkg := r.bld.NewIncrementalPackage(PackageName)
+	if err := build(pkg, files); err != nil {
+		return nil, err
+	}
+	declared := make(map[string]bool)
+	for fun := range pkg.IR().ExportedFuncs() {
+		declared[fun.Name()] = true
+	}
+	if err := build(pkg, tests); err != nil {
+		return nil, err
+	}
+	res := &Package{IR: pkg.IR(), tests: make(map[string]bool)}
+	for fun := range res.IR.ExportedFuncs() {
+		if !declared[fun.Name()] && strings.HasPrefix(fun.Name(), TestPrefix) {
+			res.tests[fun.Name()] = true
+		}
+	}
+	return res, nil
+}
+
+func build(pkg *builder.IncrementalPackage, files []File) error {
+	for _, file := range files {
+		if file.Source == "" {
+			continue
+		}
+		if err := pkg.Build(file.Source); err != nil {
+			return &fileError{name: file.Name, err: err}
+		}
+	}
+	return nil
+}
+
+// isTest returns true if a function is a test function of the package.
+func (pkg *Package) isTest(fun ir.Func) bool {
+	return pkg.tests[fun.Name()]
+}
+
+// Call a function given some arguments.
+// Extra arguments are ignored.
+func (r *Runner) Call(fun ir.Func, args []values.Value) ([]values.Value, error) {
+	numArgs := fun.FuncType().Params.Len()
+	if len(args) < numArgs {
+		return nil, fmt.Errorf("not enough arguments to pass to %s: got %d but want %d", fun.Name(), len(args), numArgs)
+	}
+	args = args[:numArgs]
+	runner, err := tracer.Trace(r.dev, fun.(*ir.FuncDecl), nil, args, nil)
+	if err != nil {
+		return nil, err
+	}
+	return runner.Run(nil, args, nil)
+}
+
+func (r *Runner) call(fun ir.Func, args []values.Value) *Result {
+	res := &Result{Name: fun.Name()}
+	start := time.Now()
+	defer func() {
+		res.Duration = time.Since(start)
+	}()
+	res.Values, res.Err = r.Call(fun, args)
+	if res.Err != nil {
+		return res
+	}
+	res.Output, res.Err = Format(res.Values)
+	return res
+}
+
+// argValues builds the values of arguments given as GX or JSON literals
+// from the types of the parameters of a function.
+// Only parameters of builtin types or arrays of builtin types are supported.
+func argValues(fun ir.Func, literals []string) ([]values.Value, error) {
+	params := fun.FuncType().Params.Fields()
+	if len(literals) != len(params) {
+		return nil, fmt.Errorf("got %d arguments but %s takes %d", len(literals), fun.Name(), len(params))
+	}
+	vals := make([]values.Value, len(params))
+	for i, param := range params {
+		var err error
+		if vals[i], err = argValue(param.Type(), literals[i]); err != nil {
+			return nil, fmt.Errorf("argument %d: %v", i+1, err)
+		}
+	}
+	return vals, nil
+}
+
+func argValue(typ ir.Type, literal string) (values.Value, error) {
+	val, err := gxlit.Parse(typ.String(), literal)
+	if err != nil {
+		return nil, err
+	}
+	switch val.DType {
+	case "bool":
+		return newValue(typ, val.Dims, val.Bools, values.AtomBoolValue, values.ArrayBoolValues)
+	case "float32":
+		return newValue(typ, val.Dims, convert[float32](val.Floats), values.AtomFloatValue[float32], values.ArrayFloatValues[float32])
+	case "float64":
+		return newValue(typ, val.Dims, val.Floats, values.AtomFloatValue[float64], values.ArrayFloatValues[float64])
+	case "int32":
+		return newValue(typ, val.Dims, convert[int32](val.Ints), values.AtomIntegerValue[int32], values.ArrayIntegerValues[int32])
+	case "int64":
+		return newValue(typ, val.Dims, val.Ints, values.AtomIntegerValue[int64], values.ArrayIntegerValues[int64])
+	case "uint32":
+		return newValue(typ, val.Dims, convert[uint32](val.Ints), values.AtomIntegerValue[uint32], values.ArrayIntegerValues[uint32])
+	case "uint64":
+		return newValue(typ, val.Dims, convert[uint64](val.Ints), values.AtomIntegerValue[uint64], values.ArrayIntegerValues[uint64])
+	}
+	return nil, fmt.Errorf("unsupported data type %s", val.DType)
+}
+
+func newValue[T any](typ ir.Type, dims []int, vals []T,
+	atom func(ir.Type, T) (*values.HostArray, error),
+	array func(ir.Type, []T, ...int) (*values.HostArray, error)) (values.Value, error) {
+	if len(dims) == 0 {
+		return atom(typ, vals[0])
+	}
+	return array(typ, vals, dims...)
+}
+
+func convert[T int32 | uint32 | uint64 | float32, S int64 | float64](src []S) []T {
+	dst := make([]T, len(src))
+	for i, v := range src {
+		dst[i] = T(v)
+	}
+	return dst
+}
+
+func findArgs(args []Args, fun ir.Func, first bool) *Args {
+	for i := range args {
+		if args[i].Func == fun.Name() || (args[i].Func == "" && first) {
+			return &args[i]
+		}
+	}
+	return nil
+}
+
+// Run calls all the exported functions of a package in order, except its test functions.
+// The values returned by a function are passed as arguments to the next function
+// unless arguments are specified for the function in args.
+// Run stops at the first function returning an error.
+func (r *Runner) Run(pkg *Package, args ...Args) []*Result {
+	var results []*Result
+	var vals []values.Value
+	first := true
+	for fun := range pkg.IR.ExportedFuncs() {
+		if pkg.isTest(fun) {
+			continue
+		}
+		if funArgs := findArgs(args, fun, first); funArgs != nil {
+			var err error
+			if vals, err = argValues(fun, funArgs.Values); err != nil {
+				results = append(results, &Result{Name: fun.Name(), Err: fmt.Errorf("invalid arguments: %v", err)})
+				break
+			}
+		}
+		first = false
+		res := r.call(fun, vals)
+		results = append(results, res)
+		if res.Err != nil {
+			break
+		}
+		vals = res.Values
+	}
+	return results
+}
+
+// RunTests calls all the test functions of a package.
+// Test functions take no argument and return a single bool. A test fails
+// if it returns an error or false.
+func (r *Runner) RunTests(pkg *Package) []*Result {
+	var results []*Result
+	for fun := range pkg.IR.ExportedFuncs() {
+		if !pkg.isTest(fun) {
+			continue
+		}
+		if !returnsBool(fun) {
+			results = append(results, &Result{Name: fun.Name(), Err: fmt.Errorf("%s does not return a single bool", fun.Name())})
+			continue
+		}
+		res := r.call(fun, nil)
+		if res.Err == nil && res.Output != "true" {
+			res.Err = fmt.Errorf("%s returned %s", res.Name, res.Output)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func returnsBool(fun ir.Func) bool {
+	results := fun.FuncType().Results.Fields()
+	return len(results) == 1 && results[0].Type().String() == "bool"
+}
+
+func flatten(out []values.Value) []values.Value {
+	flat := []values.Value{}
+	for _, v := range out {
+		slice, ok := v.(*values.Slice)
+		if !ok {
+			flat = append(flat, v)
+			continue
+		}
+		vals := make([]values.Value, slice.Size())
+		for i := 0; i < slice.Size(); i++ {
+			vals[i] = slice.Element(i)
+		}
+		flat = append(flat, flatten(vals)...)
+	}
+	return flat
+}
+
+// Format returns a string representation of values returned by a function.
+func Format(out []values.Value) (string, error) {
+	out, err := values.ToHost(kernels.Allocator(), flatten(out))
+	if err != nil {
+		return "", err
+	}
+	if len(out) == 0 {
+		return "", nil
+	}
+	if len(out) == 1 {
+		return fmt.Sprint(out[0]), nil
+	}
+	bld := strings.Builder{}
+	for i, s := range out {
+		bld.WriteString(fmt.Sprintf("%d: %v\n", i, s))
+	}
+	return bld.String(), nil
+}
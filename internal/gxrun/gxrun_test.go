@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gxrun_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gx-org/gx-org/internal/diag"
+	"github.com/gx-org/gx-org/internal/gxrun"
+)
+
+const mainSrc = `package main
+
+func TestHelper() bool {
+	return false
+}
+
+func Main() [2]bool {
+	return [2]bool{TestHelper(), false}
+}
+`
+
+const testSrc = `package main
+
+func TestPass() bool {
+	return true
+}
+
+func TestFail() bool {
+	return false
+}
+
+func TestArray() [2]bool {
+	return [2]bool{true, true}
+}
+
+func TestNumber() float32 {
+	return 1
+}
+`
+
+func TestRunTests(t *testing.T) {
+	runner := gxrun.New()
+	pkg, err := runner.CompileWithTests(
+		[]gxrun.File{{Name: "main.gx", Source: mainSrc}},
+		[]gxrun.File{{Name: "test.gx", Source: testSrc}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var funcs []string
+	for _, res := range runner.Run(pkg) {
+		if res.Err != nil {
+			t.Errorf("%s: %v", res.Name, res.Err)
+		}
+		funcs = append(funcs, res.Name)
+	}
+	if diff := cmp.Diff([]string{"TestHelper", "Main"}, funcs); diff != "" {
+		t.Errorf("unexpected functions run (-want +got):\n%s", diff)
+	}
+	got := make(map[string]bool)
+	for _, res := range runner.RunTests(pkg) {
+		got[res.Name] = res.Err == nil
+	}
+	want := map[string]bool{
+		"TestPass":   true,
+		"TestFail":   false,
+		"TestArray":  false,
+		"TestNumber": false,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected test results (-want +got):\n%s", diff)
+	}
+}
+
+// TestDiagnostics checks that the errors of the GX compiler are converted to diagnostics
+// with the end positions computed from the source of the file of the error.
+//
+// The expected positions are the positions the Go toolchain reports for the same code.
+// They have not been checked against the version of gx pinned in go.mod
+// (v0.0.0-20250609154441-6e8054fbb561), which could not be downloaded
+// when the test was written.
+func TestDiagnostics(t *testing.T) {
+	const util = "package main\n\nfunc Util() float32 {\n\treturn 1\n}\n"
+	tests := []struct {
+		files []gxrun.File
+		want  diag.Diagnostic
+	}{
+		{
+			files: []gxrun.File{{Name: "main.gx", Source: "package main\n\nfunc Main() float32 {\n\treturn undefinedVar + 1\n}\n"}},
+			want:  diag.Diagnostic{File: "main.gx", Line: 4, Column: 9, EndLine: 4, EndColumn: 21},
+		},
+		{
+			files: []gxrun.File{{Name: "main.gx", Source: "package main\n\nfunc Main() float32 {\n\treturn 1 +\n}\n"}},
+			want:  diag.Diagnostic{File: "main.gx", Line: 5, Column: 1, EndLine: 5, EndColumn: 2},
+		},
+		{
+			files: []gxrun.File{
+				{Name: "util.gx", Source: util},
+				{Name: "main.gx", Source: "package main\n\nfunc Main() float32 {\n\treturn Util() + undefinedVar\n}\n"},
+			},
+			want: diag.Diagnostic{File: "main.gx", Line: 4, Column: 18, EndLine: 4, EndColumn: 30},
+		},
+	}
+	for i, test := range tests {
+		srcs := make(map[string]string)
+		for _, file := range test.files {
+			srcs[file.Name] = file.Source
+		}
+		_, err := gxrun.New().Compile(test.files...)
+		if err == nil {
+			t.Errorf("test %d: expected a compile error", i)
+			continue
+		}
+		diags := diag.FromError(err, srcs)
+		if len(diags) == 0 {
+			t.Errorf("test %d: no diagnostic for error %v", i, err)
+			continue
+		}
+		got := diags[0]
+		if got.Message == "" || got.Severity != diag.SeverityError {
+			t.Errorf("test %d: invalid diagnostic %v", i, got)
+		}
+		got.Message, got.Severity = "", ""
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("test %d: unexpected diagnostic for error %v (-want +got):\n%s", i, err, diff)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package highlight highlights GX source code in HTML.
+//
+// The same classes are used by the editor and by the code in the text of lessons.
+package highlight
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+const (
+	// KeywordClass is the CSS class of GX keywords.
+	KeywordClass = "gx_keyword"
+	// TypeClass is the CSS class of GX builtin types.
+	TypeClass = "gx_type"
+)
+
+var classToWords = []struct {
+	class string
+	words []string
+}{
+	{
+		class: KeywordClass,
+		words: []string{
+			"var", "const", "return", "struct", "func", "package", "import", "type", "interface",
+		},
+	},
+	{
+		class: TypeClass,
+		words: []string{
+			"bool", "string",
+			"int32", "int64", "uint32", "uint64",
+			"bfloat16", "float32", "float64",
+		},
+	},
+}
+
+var (
+	wordRegexp  = regexp.MustCompile(`[\pL_][\pL\pN_]*`)
+	wordToClass = make(map[string]string)
+)
+
+func init() {
+	for _, cw := range classToWords {
+		for _, word := range cw.words {
+			wordToClass[word] = cw.class
+		}
+	}
+}
+
+// HTML returns the escaped source with GX keywords and types in spans with their class.
+func HTML(src string) string {
+	return wordRegexp.ReplaceAllStringFunc(html.EscapeString(src), func(word string) string {
+		class, ok := wordToClass[word]
+		if !ok {
+			return word
+		}
+		return `<span class="` + class + `">` + word + "</span>"
+	})
+}
+
+// Code returns the HTML of a block of GX source code.
+func Code(src string) string {
+	return `<pre class="gx_code"><code>` + HTML(strings.TrimSuffix(src, "\n")) + "</code></pre>"
+}
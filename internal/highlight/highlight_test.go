@@ -0,0 +1,46 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package highlight_test
+
+import (
+	"testing"
+
+	"github.com/gx-org/gx-org/internal/highlight"
+)
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{
+			src:  "func Main() float32 {",
+			want: `<span class="gx_keyword">func</span> Main() <span class="gx_type">float32</span> {`,
+		},
+		{
+			src:  "funcs := float32s < 1",
+			want: "funcs := float32s &lt; 1",
+		},
+		{
+			src:  "return \"x\"",
+			want: `<span class="gx_keyword">return</span> &#34;x&#34;`,
+		},
+	}
+	for i, test := range tests {
+		if got := highlight.HTML(test.src); got != test.want {
+			t.Errorf("test %d: unexpected HTML for %q:\ngot:  %s\nwant: %s", i, test.src, got, test.want)
+		}
+	}
+}
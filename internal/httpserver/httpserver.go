@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpserver provides what the overview needs to run as a production server:
+// health checks, access logs, security headers, TLS, and graceful shutdown.
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Health reports if the server is alive and ready to serve requests.
+type Health struct {
+	ready atomic.Bool
+}
+
+// SetReady sets if the server is ready to serve requests.
+func (h *Health) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Healthz reports that the server is alive.
+func (h *Health) Healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// Readyz reports if the server is ready to serve requests.
+// It fails while the server is starting or shutting down.
+func (h *Health) Readyz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !h.ready.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, "not ready")
+		return
+	}
+	fmt.Fprintln(w, "ready")
+}
+
+// AccessLog logs every request with a structured logger.
+func AccessLog(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			start := time.Now()
+			defer func() {
+				logger.LogAttrs(r.Context(), slog.LevelInfo, "request",
+					slog.String("id", middleware.GetReqID(r.Context())),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String("remote", r.RemoteAddr),
+					slog.String("user_agent", r.UserAgent()),
+					slog.Int("status", ww.Status()),
+					slog.Int("bytes", ww.BytesWritten()),
+					slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+				)
+			}()
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
+
+// ContentSecurityPolicy allows scripts and WebAssembly from the server only.
+// Inline styles are not allowed: the code editor highlights syntax with classes.
+// Images are restricted to the server and data URLs, the images kept by mdtext.Sanitize.
+const ContentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' 'wasm-unsafe-eval'; " +
+	"style-src 'self' https://fonts.googleapis.com; " +
+	"font-src https://fonts.gstatic.com; " +
+	"img-src 'self' data:; " +
+	"connect-src 'self'; " +
+	"object-src 'none'; " +
+	"base-uri 'self'; " +
+	"frame-ancestors 'none'"
+
+// SecurityHeaders sets security headers on all responses.
+// Strict-Transport-Security is only set when the server uses TLS.
+func SecurityHeaders(tls bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("Content-Security-Policy", ContentSecurityPolicy)
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("X-Frame-Options", "DENY")
+			h.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+			h.Set("Cross-Origin-Opener-Policy", "same-origin")
+			if tls {
+				h.Set("Strict-Transport-Security", "max-age=63072000; includeSubDomains")
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TLS is the certificate and key used to serve HTTPS.
+// HTTP is served if both are empty.
+type TLS struct {
+	CertFile, KeyFile string
+}
+
+// Enabled returns true if HTTPS needs to be served.
+// An error is returned if only one of the certificate or the key is specified.
+func (t TLS) Enabled() (bool, error) {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return false, fmt.Errorf("both a TLS certificate and a key are required")
+	}
+	return t.CertFile != "", nil
+}
+
+// ListenAndServe runs a server until ctx is done.
+// The server is then shut down gracefully: it stops accepting new connections
+// and waits up to shutdownTimeout for in-flight requests to complete.
+func ListenAndServe(ctx context.Context, srv *http.Server, tls TLS, shutdownTimeout time.Duration) error {
+	useTLS, err := tls.Enabled()
+	if err != nil {
+		return err
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		if useTLS {
+			serveErr <- srv.ListenAndServeTLS(tls.CertFile, tls.KeyFile)
+		} else {
+			serveErr <- srv.ListenAndServe()
+		}
+	}()
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("cannot run HTTP server: %v", err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("cannot shut down HTTP server: %v", err)
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("cannot run HTTP server: %v", err)
+	}
+	return nil
+}
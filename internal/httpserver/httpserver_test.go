@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gx-org/gx-org/internal/httpserver"
+)
+
+func status(h http.HandlerFunc) int {
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec.Code
+}
+
+func TestHealth(t *testing.T) {
+	health := &httpserver.Health{}
+	if got := status(health.Healthz); got != http.StatusOK {
+		t.Errorf("healthz: got status %d", got)
+	}
+	if got := status(health.Readyz); got != http.StatusServiceUnavailable {
+		t.Errorf("readyz before ready: got status %d", got)
+	}
+	health.SetReady(true)
+	if got := status(health.Readyz); got != http.StatusOK {
+		t.Errorf("readyz when ready: got status %d", got)
+	}
+}
+
+func TestMiddlewares(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	handler := httpserver.AccessLog(logger)(httpserver.SecurityHeaders(true)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("tea"))
+	})))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pot", nil))
+	for _, header := range []string{"Content-Security-Policy", "X-Content-Type-Options", "Strict-Transport-Security"} {
+		if rec.Header().Get(header) == "" {
+			t.Errorf("header %s not set", header)
+		}
+	}
+	var entry struct {
+		Method string
+		Path   string
+		Status int
+		Bytes  int
+	}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("cannot decode log entry %q: %v", logs.String(), err)
+	}
+	if entry.Method != http.MethodGet || entry.Path != "/pot" || entry.Status != http.StatusTeapot || entry.Bytes != 3 {
+		t.Errorf("unexpected log entry: %s", logs.String())
+	}
+}
+
+func TestGracefulShutdown(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	started := make(chan bool)
+	srv := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("done"))
+	})}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error)
+	go func() {
+		served <- httpserver.ListenAndServe(ctx, srv, httpserver.TLS{}, time.Minute)
+	}()
+	var resp *http.Response
+	got := make(chan error)
+	go func() {
+		for {
+			resp, err = http.Get("http://" + addr)
+			if err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		got <- err
+	}()
+	<-started
+	cancel()
+	if err := <-got; err != nil {
+		t.Fatalf("in-flight request failed: %v", err)
+	}
+	resp.Body.Close()
+	if err := <-served; err != nil {
+		t.Errorf("unexpected error after shutdown: %v", err)
+	}
+}
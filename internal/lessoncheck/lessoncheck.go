@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lessoncheck compiles and runs the code of lessons natively.
+package lessoncheck
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/gx-org/gx-org/internal/golden"
+	"github.com/gx-org/gx-org/internal/gxrun"
+	"github.com/gx-org/gx-org/internal/lessons"
+)
+
+// Failure of a lesson.
+type Failure struct {
+	Lesson *lessons.Lesson
+	Err    error
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("%s: %v", f.Lesson.File, f.Err)
+}
+
+func (f *Failure) Unwrap() error {
+	return f.Err
+}
+
+func failure(les *lessons.Lesson, what string, err error) error {
+	return &Failure{Lesson: les, Err: fmt.Errorf("%s: %v", what, err)}
+}
+
+func gxFiles(files []lessons.SourceFile) []gxrun.File {
+	gxFiles := make([]gxrun.File, len(files))
+	for i, file := range files {
+		gxFiles[i] = gxrun.File(file)
+	}
+	return gxFiles
+}
+
+func gxArgs(args []lessons.FuncArgs) []gxrun.Args {
+	gxArgs := make([]gxrun.Args, len(args))
+	for i, fa := range args {
+		gxArgs[i] = gxrun.Args(fa)
+	}
+	return gxArgs
+}
+
+// run compiles files with the tests of a lesson and runs its exported functions
+// as the overview does in the browser. It returns the output
+// of the last function. Tests are run only if runTests is true.
+func run(les *lessons.Lesson, what string, files []lessons.SourceFile, runTests bool) (output string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = failure(les, what, fmt.Errorf("GX PANIC: %v\n%s", r, debug.Stack()))
+		}
+	}()
+	runner := gxrun.New(les.Packages()...)
+	pkg, err := runner.CompileWithTests(gxFiles(files), gxFiles(les.TestFiles()))
+	if err != nil {
+		return "", failure(les, what, fmt.Errorf("cannot compile: %v", err))
+	}
+	for _, res := range runner.Run(pkg, gxArgs(les.Args)...) {
+		if res.Err != nil {
+			return "", failure(les, what, fmt.Errorf("%s: %v", res.Name, res.Err))
+		}
+		output = res.Output
+	}
+	if !runTests {
+		return output, nil
+	}
+	for _, res := range runner.RunTests(pkg) {
+		if res.Err != nil {
+			return "", failure(les, what, fmt.Errorf("%s failed: %v", res.Name, res.Err))
+		}
+	}
+	return output, nil
+}
+
+// Run compiles and runs the code of a lesson with its tests.
+// If the lesson has a solution, the solution is also run and needs to pass the tests.
+// Tests are not run on the code of the lesson because the code of an exercise
+// is not expected to pass them.
+// Run returns the output of the solution if any, or the output of the code otherwise.
+func Run(les *lessons.Lesson) (string, error) {
+	output, err := run(les, "code", les.Sources, false)
+	if err != nil || len(les.Solution) == 0 {
+		return output, err
+	}
+	return run(les, "solution", les.SolutionFiles(), true)
+}
+
+// Lesson runs a lesson and checks its output
+// against the expected output of the lesson if any.
+func Lesson(les *lessons.Lesson) error {
+	output, err := Run(les)
+	if err != nil {
+		return err
+	}
+	if les.Output == "" {
+		return nil
+	}
+	if err := golden.Match(output, les.Output); err != nil {
+		return &Failure{Lesson: les, Err: fmt.Errorf("unexpected output: %v\ngot:\n%s\nwant:\n%s", err, output, les.Output)}
+	}
+	return nil
+}
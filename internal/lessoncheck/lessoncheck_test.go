@@ -0,0 +1,38 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lessoncheck_test
+
+import (
+	"testing"
+
+	"github.com/gx-org/gx-org/internal/lessoncheck"
+	"github.com/gx-org/gx-org/internal/lessons"
+)
+
+func TestLessons(t *testing.T) {
+	chapters, err := lessons.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chap := range chapters {
+		for _, les := range chap.Content {
+			t.Run(les.File, func(t *testing.T) {
+				if err := lessoncheck.Lesson(les); err != nil {
+					t.Error(err)
+				}
+			})
+		}
+	}
+}
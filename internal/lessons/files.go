@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lessons
+
+import (
+	"regexp"
+
+	"github.com/gx-org/gx-org/internal/mdtext"
+	"golang.org/x/tools/txtar"
+)
+
+const (
+	// MainFile is the name of the file of a code block without a file name.
+	MainFile = "main.gx"
+	// TestFile is the name of the file of the hidden tests of a lesson.
+	TestFile = "test.gx"
+)
+
+// SourceFile is a GX source file of a lesson.
+type SourceFile struct {
+	Name   string
+	Source string
+}
+
+func sourceFiles(fileName string, blocks []mdtext.CodeFile, tag string) ([]SourceFile, error) {
+	var files []SourceFile
+	names := make(map[string]int)
+	for _, block := range blocks {
+		name := block.Name
+		if name == "" {
+			name = MainFile
+		}
+		if line, ok := names[name]; ok {
+			return nil, mdtext.Errorf(fileName, block.Line, "file %s already specified in the %s block at line %d", name, tag, line)
+		}
+		names[name] = block.Line
+		files = append(files, SourceFile{Name: name, Source: block.Code})
+	}
+	return files, nil
+}
+
+// highlights returns the lines to highlight in the code blocks given the name of their file.
+func highlights(blocks []mdtext.CodeFile) map[string][]mdtext.LineRange {
+	lines := make(map[string][]mdtext.LineRange)
+	for _, block := range blocks {
+		if len(block.Highlight) == 0 {
+			continue
+		}
+		name := block.Name
+		if name == "" {
+			name = MainFile
+		}
+		lines[name] = block.Highlight
+	}
+	return lines
+}
+
+// FuncArgs are the arguments passed to a function when the code of a lesson is run.
+type FuncArgs struct {
+	// Func is the name of the function.
+	// Arguments are passed to the first function if Func is empty.
+	Func string
+	// Values of the arguments as GX or JSON literals.
+	Values []string
+}
+
+func funcArgs(fileName string, blocks []mdtext.FuncArgs, sources []SourceFile) ([]FuncArgs, error) {
+	args := make([]FuncArgs, len(blocks))
+	for i, block := range blocks {
+		if block.Func != "" && !declaresFunc(sources, block.Func) {
+			return nil, mdtext.Errorf(fileName, block.Line, "arguments for unknown function %s", block.Func)
+		}
+		args[i] = FuncArgs{Func: block.Func, Values: block.Values}
+	}
+	return args, nil
+}
+
+// declaresFunc returns true if a function is declared at the top level of a source file.
+func declaresFunc(sources []SourceFile, name string) bool {
+	decl := regexp.MustCompile(`(?m)^func\s+` + regexp.QuoteMeta(name) + `\s*\(`)
+	for _, file := range sources {
+		if decl.MatchString(file.Source) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindFile returns the file given its name or nil if the file does not exist.
+func FindFile(files []SourceFile, name string) *SourceFile {
+	for i := range files {
+		if files[i].Name == name {
+			return &files[i]
+		}
+	}
+	return nil
+}
+
+// TestFiles returns the file of the hidden tests of the lesson or nil if the lesson has no test.
+func (les *Lesson) TestFiles() []SourceFile {
+	if les.Test == "" {
+		return nil
+	}
+	return []SourceFile{{Name: TestFile, Source: les.Test}}
+}
+
+// SolutionFiles returns the source files of the lesson
+// in which the files with a solution are replaced by their solution.
+func (les *Lesson) SolutionFiles() []SourceFile {
+	files := make([]SourceFile, len(les.Sources))
+	for i, file := range les.Sources {
+		if solution := FindFile(les.Solution, file.Name); solution != nil {
+			file = *solution
+		}
+		files[i] = file
+	}
+	return files
+}
+
+// FormatFiles returns the files as a single string.
+// A single file is returned as is. Several files are returned as a txtar archive.
+func FormatFiles(files []SourceFile) string {
+	if len(files) == 1 {
+		return files[0].Source
+	}
+	ar := &txtar.Archive{}
+	for _, file := range files {
+		ar.Files = append(ar.Files, txtar.File{Name: file.Name, Data: []byte(file.Source)})
+	}
+	return string(txtar.Format(ar))
+}
+
+// ParseFiles parses a string returned by FormatFiles.
+// A string without txtar file markers is returned as a single file named MainFile.
+func ParseFiles(s string) []SourceFile {
+	ar := txtar.Parse([]byte(s))
+	if len(ar.Files) == 0 {
+		return []SourceFile{{Name: MainFile, Source: s}}
+	}
+	files := make([]SourceFile, len(ar.Files))
+	for i, file := range ar.Files {
+		files[i] = SourceFile{Name: file.Name, Source: string(file.Data)}
+	}
+	return files
+}
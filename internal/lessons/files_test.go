@@ -0,0 +1,38 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lessons_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gx-org/gx-org/internal/lessons"
+)
+
+func TestFormatFiles(t *testing.T) {
+	tests := [][]lessons.SourceFile{
+		{{Name: lessons.MainFile, Source: "package main\n"}},
+		{
+			{Name: "model.gx", Source: "package main\n\nfunc Model() float32 {\n\treturn 1\n}\n"},
+			{Name: lessons.MainFile, Source: "package main\n\nfunc Main() float32 {\n\treturn Model()\n}\n"},
+		},
+	}
+	for i, files := range tests {
+		got := lessons.ParseFiles(lessons.FormatFiles(files))
+		if diff := cmp.Diff(files, got); diff != "" {
+			t.Errorf("test %d: unexpected files (-want +got):\n%s", i, diff)
+		}
+	}
+}
@@ -15,17 +15,24 @@
 package lessons
 
 import (
-	"errors"
+	"bytes"
 	"fmt"
-	"os"
+	"io/fs"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/gx-org/gx-org/internal/mdtext"
 	"github.com/gx-org/gx-org/lessons"
+	"gopkg.in/yaml.v3"
 )
 
 type (
 	Chapter struct {
 		titleHTML string
+		Title     string
 		Content   []*Lesson
 		ID        int
 	}
@@ -33,75 +40,212 @@ type (
 	Lesson struct {
 		Chapter *Chapter
 		ID      int
+		// File is the name of the markdown file of the lesson.
+		File string
+		// Slug identifies the lesson in URLs.
+		// It defaults to the name of the file without extension.
+		Slug string
+		// Title of the lesson given in its front matter.
+		// It defaults to the title of the chapter, that is the # title of its first lesson.
+		Title string
+		// Meta is the metadata of the lesson given in its front matter.
+		Meta mdtext.FrontMatter
 
 		HTML string
-		Code string
+		// Sources are the GX source files of the lesson in the order in which they are displayed.
+		// All the files are compiled into the same package.
+		Sources []SourceFile
+		// Highlights are the lines to highlight in the source files given their name.
+		Highlights map[string][]mdtext.LineRange
+		// Args are the arguments passed to the functions of the code.
+		Args []FuncArgs
+		// Output is the expected output of the code.
+		// Output is empty if the lesson does not specify an expected output.
+		Output string
+		// Test is the GX source of the hidden test functions of the lesson.
+		Test string
+		// PackageDirs are the folders, relative to the lessons folder,
+		// of the GX packages importable from the code of the lesson.
+		// Packages of the lesson come before packages of its chapter.
+		PackageDirs []string
+		// Solution are the source files replaced by their solution in the exercise of the lesson.
+		Solution []SourceFile
+		// Snippets are the sources of the GX code blocks in the text of the lesson.
+		// They can be loaded into the editor but are not run by the lesson checker.
+		Snippets []string
+		// Links are the lessons linked from the text of the lesson given their slug.
+		Links map[string]*Lesson
 
 		Prev *Lesson
 		Next *Lesson
 	}
 )
 
+// CourseFile is the manifest listing the chapters of the course and their lessons in order.
+const CourseFile = "course.yaml"
+
+type (
+	course struct {
+		// TrustedHTML keeps the raw HTML of the lessons instead of sanitizing it.
+		// Only set it for courses written by trusted authors.
+		TrustedHTML bool              `yaml:"trusted_html"`
+		Chapters    []chapterManifest `yaml:"chapters"`
+	}
+
+	chapterManifest struct {
+		// Lessons are the names of the markdown files of the lessons.
+		Lessons []string `yaml:"lessons"`
+		// Packages is a folder of GX packages importable from all the lessons of the chapter.
+		Packages string `yaml:"packages"`
+	}
+)
+
+var slugRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func readCourse() (*course, error) {
+	data, err := lessons.Lessons.ReadFile(CourseFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %v", CourseFile, err)
+	}
+	crs := &course{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(crs); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %v", CourseFile, err)
+	}
+	return crs, nil
+}
+
+// New reads the chapters and lessons of the course in the order given by CourseFile.
 func New() ([]*Chapter, error) {
+	crs, err := readCourse()
+	if err != nil {
+		return nil, err
+	}
+	var opts []mdtext.Option
+	if crs.TrustedHTML {
+		opts = append(opts, mdtext.TrustedHTML())
+	}
 	var chapters []*Chapter
-	chapterFound := true
 	var prev *Lesson
-	for chapterFound {
+	slugs := make(map[string]*Lesson)
+	for _, chapManifest := range crs.Chapters {
 		chap := &Chapter{ID: len(chapters) + 1}
-		lessonFound := true
-		for lessonFound {
-			lesson, err := readLesson(chap)
+		if len(chapManifest.Lessons) == 0 {
+			return nil, fmt.Errorf("%s: chapter %d has no lesson", CourseFile, chap.ID)
+		}
+		if err := checkPackages(chapManifest.Packages); err != nil {
+			return nil, fmt.Errorf("%s: chapter %d: %v", CourseFile, chap.ID, err)
+		}
+		for _, fileName := range chapManifest.Lessons {
+			lesson, err := readLesson(chap, fileName, opts)
 			if err != nil {
 				return nil, err
 			}
-			if lesson != nil {
-				lessonFound = true
-				if prev != nil {
-					lesson.Prev = prev
-					prev.Next = lesson
-				}
-				prev = lesson
-			} else {
-				lessonFound = false
+			if chapManifest.Packages != "" {
+				lesson.PackageDirs = append(lesson.PackageDirs, chapManifest.Packages)
 			}
-		}
-		if len(chap.Content) == 0 {
-			break
+			if other := slugs[lesson.Slug]; other != nil {
+				return nil, fmt.Errorf("%s: slug %q already used by %s", fileName, lesson.Slug, other.File)
+			}
+			slugs[lesson.Slug] = lesson
+			if prev != nil {
+				lesson.Prev = prev
+				prev.Next = lesson
+			}
+			prev = lesson
 		}
 		chapters = append(chapters, chap)
 	}
 	if len(chapters) == 0 {
 		return nil, fmt.Errorf("no content found")
 	}
+	if err := resolveLinks(chapters, slugs); err != nil {
+		return nil, err
+	}
 	return chapters, nil
 }
 
-func readLesson(chap *Chapter) (*Lesson, error) {
+// resolveLinks sets the lessons linked from the text of each lesson.
+func resolveLinks(chapters []*Chapter, slugs map[string]*Lesson) error {
+	for _, chap := range chapters {
+		for _, les := range chap.Content {
+			for _, slug := range slices.Sorted(maps.Keys(les.Links)) {
+				target := slugs[slug]
+				if target == nil {
+					return fmt.Errorf("%s: link to unknown lesson %q", les.File, slug)
+				}
+				les.Links[slug] = target
+			}
+		}
+	}
+	return nil
+}
+
+func readLesson(chap *Chapter, fileName string, opts []mdtext.Option) (*Lesson, error) {
 	lessonID := len(chap.Content) + 1
-	fileName := fmt.Sprintf("%d_%d.md", chap.ID, lessonID)
 	data, err := lessons.Lessons.ReadFile(fileName)
-	if errors.Is(err, os.ErrNotExist) && (chap.ID > 1 || lessonID > 1) {
-		return nil, nil
-	}
 	if err != nil {
 		return nil, fmt.Errorf("cannot read %s: %v", fileName, err)
 	}
-	mdt := mdtext.Parse(data)
-	lesson := &Lesson{Chapter: chap, ID: lessonID}
+	mdt, err := mdtext.ParseFile(fileName, data, opts...)
+	if err != nil {
+		return nil, err
+	}
+	lesson := &Lesson{Chapter: chap, ID: lessonID, File: fileName, Meta: mdt.FrontMatter}
+	lesson.Slug = mdt.FrontMatter.Slug
+	if lesson.Slug == "" {
+		lesson.Slug = strings.TrimSuffix(fileName, path.Ext(fileName))
+	}
+	if !slugRegexp.MatchString(lesson.Slug) {
+		return nil, mdtext.Errorf(fileName, 0, "invalid slug %q: only letters, digits, - and _ are allowed", lesson.Slug)
+	}
+	if err := checkPackages(mdt.FrontMatter.Packages); err != nil {
+		return nil, &mdtext.Error{File: fileName, Err: err}
+	}
+	if mdt.FrontMatter.Packages != "" {
+		lesson.PackageDirs = append(lesson.PackageDirs, mdt.FrontMatter.Packages)
+	}
 	if mdt.TitleHTML != "" && lessonID != 1 {
-		return nil, fmt.Errorf("%s: chapter title can only be specified for the first lesson", fileName)
+		return nil, mdtext.Errorf(fileName, mdt.TitleLine, "chapter title can only be specified for the first lesson of a chapter")
 	}
 	if mdt.TitleHTML == "" && lessonID == 1 {
-		return nil, fmt.Errorf("%s: no chapter title specified", fileName)
+		return nil, mdtext.Errorf(fileName, 0, "no chapter title specified: the first lesson of a chapter needs a # title")
 	}
 	if lessonID == 1 {
 		chap.titleHTML = mdt.TitleHTML
+		chap.Title = mdt.Title
+	}
+	lesson.Title = mdt.FrontMatter.Title
+	if lesson.Title == "" {
+		lesson.Title = chap.Title
 	}
 	lesson.HTML = chap.titleHTML + "\n\n" + mdt.HTML
-	lesson.Code = mdt.Code[mdtext.TagPrefix+"code"]
-	if lesson.Code == "" {
-		return nil, fmt.Errorf("lesson %s has no GX source code", fileName)
+	if lesson.Sources, err = sourceFiles(fileName, mdt.Sources, mdtext.CodeTag); err != nil {
+		return nil, err
+	}
+	if len(lesson.Sources) == 0 {
+		return nil, mdtext.Errorf(fileName, 0, "no GX source code: the lesson needs a %s block", mdtext.CodeTag)
+	}
+	lesson.Highlights = highlights(mdt.Sources)
+	if lesson.Solution, err = sourceFiles(fileName, mdt.Solution, mdtext.SolutionTag); err != nil {
+		return nil, err
 	}
+	for i, file := range lesson.Solution {
+		if FindFile(lesson.Sources, file.Name) == nil {
+			return nil, mdtext.Errorf(fileName, mdt.Solution[i].Line, "solution for unknown file %s", file.Name)
+		}
+	}
+	if lesson.Args, err = funcArgs(fileName, mdt.Args, lesson.Sources); err != nil {
+		return nil, err
+	}
+	lesson.Snippets = mdt.Snippets
+	lesson.Links = make(map[string]*Lesson)
+	for _, slug := range mdt.LessonLinks {
+		lesson.Links[slug] = nil
+	}
+	lesson.Output = mdt.Output
+	lesson.Test = mdt.Test
 	chap.Content = append(chap.Content, lesson)
 	return lesson, nil
 }
@@ -113,14 +257,14 @@ func (chap *Chapter) NumLessons() int {
 func FindLesson(chapters []*Chapter, chapID, lessonID int) *Lesson {
 	chapI := chapID - 1
 	lessonI := lessonID - 1
-	if chapI <= 0 {
+	if chapI < 0 {
 		return chapters[0].Content[0]
 	}
 	if chapI >= len(chapters) {
 		return chapters[0].Content[0]
 	}
 	chap := chapters[chapI]
-	if lessonI <= 0 {
+	if lessonI < 0 {
 		return chap.Content[0]
 	}
 	if lessonI >= len(chap.Content) {
@@ -128,3 +272,57 @@ func FindLesson(chapters []*Chapter, chapID, lessonID int) *Lesson {
 	}
 	return chap.Content[lessonI]
 }
+
+// Lookup returns the lesson given its chapter and lesson IDs
+// or nil if the lesson does not exist.
+func Lookup(chapters []*Chapter, chapID, lessonID int) *Lesson {
+	if chapID < 1 || chapID > len(chapters) {
+		return nil
+	}
+	chap := chapters[chapID-1]
+	if lessonID < 1 || lessonID > len(chap.Content) {
+		return nil
+	}
+	return chap.Content[lessonID-1]
+}
+
+func checkPackages(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	info, err := fs.Stat(lessons.Lessons, dir)
+	if err != nil {
+		return fmt.Errorf("cannot find packages folder: %v", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("packages %s is not a folder", dir)
+	}
+	return nil
+}
+
+// Packages returns the file systems of the GX packages importable from the code of the lesson.
+// The path of a package in a file system is its import path.
+func (les *Lesson) Packages() []fs.FS {
+	var fss []fs.FS
+	for _, dir := range les.PackageDirs {
+		sub, err := fs.Sub(lessons.Lessons, dir)
+		if err != nil {
+			// Folders are checked when lessons are read.
+			panic(err)
+		}
+		fss = append(fss, sub)
+	}
+	return fss
+}
+
+// BySlug returns the lesson given its slug or nil if the lesson does not exist.
+func BySlug(chapters []*Chapter, slug string) *Lesson {
+	for _, chap := range chapters {
+		for _, les := range chap.Content {
+			if les.Slug == slug {
+				return les
+			}
+		}
+	}
+	return nil
+}
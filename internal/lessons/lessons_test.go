@@ -0,0 +1,76 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lessons_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gx-org/gx-org/internal/gxrun"
+	"github.com/gx-org/gx-org/internal/lessons"
+)
+
+func TestPackages(t *testing.T) {
+	chapters, err := lessons.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	les := lessons.BySlug(chapters, "importing-packages")
+	if les == nil {
+		t.Fatal("lesson importing-packages not found")
+	}
+	pkgs := les.Packages()
+	if len(pkgs) == 0 {
+		t.Fatalf("lesson %s has no package", les.File)
+	}
+	if _, err := fs.Stat(pkgs[0], "tour/helpers/helpers.gx"); err != nil {
+		t.Errorf("package tour/helpers not found: %v", err)
+	}
+	src := les.Sources[0].Source
+	if !strings.Contains(src, `import "tour/helpers"`) {
+		t.Fatalf("lesson %s does not import tour/helpers:\n%s", les.File, src)
+	}
+	if _, err := gxrun.New(pkgs...).Compile(gxrun.File(les.Sources[0])); err != nil {
+		t.Errorf("cannot compile lesson %s importing tour/helpers: %v", les.File, err)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	chapters, err := lessons.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	les := lessons.BySlug(chapters, "function-arguments")
+	if les == nil {
+		t.Fatal("lesson function-arguments not found")
+	}
+	want := []lessons.FuncArgs{{Func: "Add", Values: []string{"[1, 2]", "[2]float32{3, 4}"}}}
+	if diff := cmp.Diff(want, les.Args); diff != "" {
+		t.Errorf("unexpected arguments (-want +got):\n%s", diff)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	chapters, err := lessons.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	les := lessons.BySlug(chapters, "function-arguments")
+	if got, want := les.Links["builtin-types"], lessons.BySlug(chapters, "builtin-types"); got != want {
+		t.Errorf("link to builtin-types resolved to %v but want %v", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package linediff computes the differences between two texts line by line.
+package linediff
+
+import "strings"
+
+// Op is the operation to apply to a line to go from the old text to the new text.
+type Op int
+
+const (
+	// Equal is a line present in both texts.
+	Equal Op = iota
+	// Delete is a line only present in the old text.
+	Delete
+	// Insert is a line only present in the new text.
+	Insert
+)
+
+// Line of a diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// Diff returns the lines to delete from old and to insert from new
+// to go from old to new. Lines are compared ignoring trailing whitespace.
+// The diff is computed from the longest common subsequence of lines.
+func Diff(old, new string) []Line {
+	a, b := splitLines(old), splitLines(new)
+	eq := func(i, j int) bool {
+		return strings.TrimRight(a[i], " \t") == strings.TrimRight(b[j], " \t")
+	}
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if eq(i, j) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var lines []Line
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case eq(i, j):
+			lines = append(lines, Line{Op: Equal, Text: b[j]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: Delete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: Insert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Op: Delete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Op: Insert, Text: b[j]})
+	}
+	return lines
+}
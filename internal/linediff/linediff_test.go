@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linediff_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gx-org/gx-org/internal/linediff"
+)
+
+func TestDiff(t *testing.T) {
+	const (
+		eq  = linediff.Equal
+		del = linediff.Delete
+		ins = linediff.Insert
+	)
+	tests := []struct {
+		old, new string
+		want     []linediff.Line
+	}{
+		{},
+		{
+			old:  "a\nb\n",
+			new:  "a\nb",
+			want: []linediff.Line{{eq, "a"}, {eq, "b"}},
+		},
+		{
+			new:  "a\n",
+			want: []linediff.Line{{ins, "a"}},
+		},
+		{
+			old:  "a\n",
+			want: []linediff.Line{{del, "a"}},
+		},
+		{
+			old: "func Main() {\n\treturn 0\n}\n",
+			new: "func Main() {\n\tx := 1\n\treturn x\n}\n",
+			want: []linediff.Line{
+				{eq, "func Main() {"},
+				{del, "\treturn 0"},
+				{ins, "\tx := 1"},
+				{ins, "\treturn x"},
+				{eq, "}"},
+			},
+		},
+		{
+			old:  "a  \nb\nc\n",
+			new:  "a\nc\nd\n",
+			want: []linediff.Line{{eq, "a"}, {del, "b"}, {eq, "c"}, {ins, "d"}},
+		},
+	}
+	for i, test := range tests {
+		got := linediff.Diff(test.old, test.new)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("test %d: unexpected diff (-want +got):\n%s", i, diff)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package livereload reloads browser pages when the sources of the overview change.
+//
+// Pages subscribe to a Server-Sent Events stream served by a Hub.
+// A reload event is sent to all subscribers after a successful rebuild.
+package livereload
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gx-org/gx-org/internal/wasmbuild"
+)
+
+// ReloadEvent is the name of the event sent to pages when they need to reload.
+const ReloadEvent = "reload"
+
+// Hub keeps track of the pages subscribed to reload events.
+type Hub struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]bool
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewHub returns a hub without subscribers.
+func NewHub() *Hub {
+	return &Hub{
+		clients: make(map[chan struct{}]bool),
+		done:    make(chan struct{}),
+	}
+}
+
+// Close ends all the event streams, for example when the server shuts down.
+func (h *Hub) Close() {
+	h.closeOnce.Do(func() {
+		close(h.done)
+	})
+}
+
+// Notify all subscribers that they need to reload.
+func (h *Hub) Notify() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for client := range h.clients {
+		select {
+		case client <- struct{}{}:
+		default:
+			// A reload is already pending for this client.
+		}
+	}
+}
+
+func (h *Hub) subscribe() chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	client := make(chan struct{}, 1)
+	h.clients[client] = true
+	return client
+}
+
+func (h *Hub) unsubscribe(client chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, client)
+}
+
+// ServeHTTP streams reload events to a page.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	client := h.subscribe()
+	defer h.unsubscribe(client)
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.done:
+			return
+		case <-client:
+			if _, err := fmt.Fprintf(w, "event: %s\ndata:\n\n", ReloadEvent); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Watch polls the sources of the module rooted at moduleRoot until ctx is done.
+// When the sources change, the WASM binary is rebuilt and, if the build succeeds,
+// all subscribers are notified.
+func (h *Hub) Watch(ctx context.Context, moduleRoot string, bld *wasmbuild.Builder, interval time.Duration) {
+	last, err := wasmbuild.SourceHash(moduleRoot)
+	if err != nil {
+		fmt.Printf("ERROR: cannot watch %s: %v\n", moduleRoot, err)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		hash, err := wasmbuild.SourceHash(moduleRoot)
+		if err != nil {
+			fmt.Printf("ERROR: cannot watch %s: %v\n", moduleRoot, err)
+			continue
+		}
+		if hash == last {
+			continue
+		}
+		last = hash
+		fmt.Println("Sources changed: rebuilding WASM")
+		if err := bld.Build(); err != nil {
+			fmt.Printf("ERROR: %v\n", err)
+			continue
+		}
+		h.Notify()
+	}
+}
@@ -15,49 +15,50 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"net/http"
 	"os"
-	"os/exec"
+	"os/signal"
+	"path"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	overview "github.com/gx-org/gx-org"
+	"github.com/gx-org/gx-org/internal/assets"
+	"github.com/gx-org/gx-org/internal/httpserver"
+	"github.com/gx-org/gx-org/internal/lessons"
+	"github.com/gx-org/gx-org/internal/livereload"
+	"github.com/gx-org/gx-org/internal/project"
+	"github.com/gx-org/gx-org/internal/share"
+	"github.com/gx-org/gx-org/internal/wasmbuild"
+	"github.com/gx-org/gx-org/internal/webapi"
+	"github.com/gx-org/gx-org/internal/webapi/worker"
 )
 
 var (
-	port     = flag.Int("port", 8080, "http port")
-	local    = flag.Bool("local", true, "local connections only")
-	logQuery = flag.Bool("logq", true, "log queries")
+	port       = flag.Int("port", 8080, "http port")
+	local      = flag.Bool("local", true, "local connections only")
+	logQuery   = flag.Bool("logq", true, "log queries")
+	dev        = flag.Bool("dev", true, "development mode: rebuild the WASM binary on demand and disable caching (set to false in production)")
+	runTimeout = flag.Duration("run_timeout", webapi.DefaultRunLimits().Timeout, "maximum time to compile and run GX code")
+	runQueue   = flag.Duration("run_queue_timeout", webapi.DefaultRunLimits().QueueTimeout, "maximum time to wait for a worker to run GX code")
+	runMemory  = flag.Int64("run_memory", webapi.DefaultRunLimits().MaxMemoryBytes>>20, "maximum memory in MiB to run GX code")
+	runSource  = flag.Int64("run_source", webapi.DefaultRunLimits().MaxSourceBytes>>10, "maximum size in KiB of GX code to run")
+	runWorkers = flag.Int("run_workers", webapi.DefaultRunLimits().MaxWorkers, "maximum number of GX programs running concurrently")
+	shareDir   = flag.String("share_dir", "", "folder in which shared snippets are stored (default to a folder in the user cache)")
+	watch      = flag.Duration("watch", time.Second, "interval at which sources are checked for changes to reload pages (0 to disable)")
+	tlsCert    = flag.String("tls_cert", "", "TLS certificate file to serve HTTPS (requires --tls_key)")
+	tlsKey     = flag.String("tls_key", "", "TLS key file to serve HTTPS (requires --tls_cert)")
+	shutdown   = flag.Duration("shutdown_timeout", 30*time.Second, "maximum time to wait for in-flight requests when shutting down")
 )
 
-func runGoGenerate() error {
-	wdir, err := os.Getwd()
-	if err != nil {
-		return err
-	}
-	moduleRoot, err := findParent("go.mod")
-	if err != nil {
-		return err
-	}
-	if err := os.Chdir(moduleRoot); err != nil {
-		return err
-	}
-	defer func() {
-		os.Chdir(wdir)
-	}()
-	cmd := exec.Command("go", "generate", "./...")
-	out, err := cmd.CombinedOutput()
-	if len(out) > 0 {
-		fmt.Println(string(out))
-	}
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
 func buildAddr() string {
 	addr := fmt.Sprintf(":%d", *port)
 	if *local {
@@ -66,24 +67,21 @@ func buildAddr() string {
 	return addr
 }
 
-func findParent(folder string) (string, error) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return "", err
-	}
-	for cwd != "/" {
-		modPath := filepath.Join(cwd, folder)
-		if _, err := os.Stat(modPath); err == nil {
-			return cwd, nil
+func newShareStore() (share.Store, error) {
+	dir := *shareDir
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
 		}
-		cwd = filepath.Dir(cwd)
+		dir = filepath.Join(cacheDir, "gx-org", "share")
 	}
-	return "", fmt.Errorf("parent %s not found", folder)
+	return share.NewFileStore(dir)
 }
 
-func mainHandler(fs http.FileSystem, w http.ResponseWriter, r *http.Request) {
+func mainHandler(fs http.FileSystem, wasm *wasmbuild.Builder, w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path == "/res/main.wasm" {
-		if err := runGoGenerate(); err != nil {
+		if err := wasm.Build(); err != nil {
 			http.Error(w, fmt.Sprintf("cannot generate WASM file: %v", err), http.StatusInternalServerError)
 			return
 		}
@@ -91,31 +89,148 @@ func mainHandler(fs http.FileSystem, w http.ResponseWriter, r *http.Request) {
 	http.FileServer(fs).ServeHTTP(w, r)
 }
 
-func run() error {
-	r := chi.NewRouter()
-	r.Use(middleware.NoCache)
-	if *logQuery {
-		r.Use(middleware.Logger)
+func addAPIRoutes(r chi.Router, lessonsAPI *webapi.Lessons) error {
+	runner, err := webapi.NewRunner(webapi.RunLimits{
+		MaxSourceBytes: *runSource << 10,
+		Timeout:        *runTimeout,
+		QueueTimeout:   *runQueue,
+		MaxMemoryBytes: *runMemory << 20,
+		MaxWorkers:     *runWorkers,
+	})
+	if err != nil {
+		return err
 	}
-	projectRoot, err := findParent(".git")
+	r.Post("/api/run", runner.ServeHTTP)
+	r.Post("/api/check", runner.Check)
+	store, err := newShareStore()
 	if err != nil {
 		return err
 	}
+	r.Route("/api/share", webapi.NewShare(store, *runSource<<10).Route)
+	r.Route("/api/lessons", lessonsAPI.Route)
+	return nil
+}
+
+// addDevRoutes serves the files from the project, rebuilding the WASM binary
+// and reloading pages when sources change.
+func addDevRoutes(ctx context.Context, srv *http.Server, r chi.Router, projectRoot string) error {
+	moduleRoot, err := project.ModuleRoot()
+	if err != nil {
+		return err
+	}
+	wasm := wasmbuild.NewBuilder(moduleRoot, filepath.Join(projectRoot, assets.ResFolder, "main.wasm"))
+	reload := livereload.NewHub()
+	srv.RegisterOnShutdown(reload.Close)
+	if *watch > 0 {
+		go reload.Watch(ctx, moduleRoot, wasm, *watch)
+	}
+	r.Get("/api/reload", reload.ServeHTTP)
 	projectFS := http.FS(os.DirFS(projectRoot))
 	r.Get("/*", func(w http.ResponseWriter, r *http.Request) {
-		mainHandler(projectFS, w, r)
+		mainHandler(projectFS, wasm, w, r)
+	})
+	return nil
+}
+
+// addProdRoutes serves content-hashed and precompressed files.
+// The WASM binary and the compressed files need to have been generated beforehand.
+func addProdRoutes(r chi.Router, files fs.FS) error {
+	if _, err := fs.Stat(files, path.Join(assets.ResFolder, "main.wasm")); err != nil {
+		return fmt.Errorf("WASM binary not found (run go generate ./... first): %v", err)
+	}
+	set, err := assets.New(files)
+	if err != nil {
+		return err
+	}
+	r.Get("/*", set.ServeHTTP)
+	r.Head("/*", set.ServeHTTP)
+	return nil
+}
+
+// addFileRoutes serves the files embedded in the binary if any.
+// Otherwise, files are served from the project on disk.
+func addFileRoutes(ctx context.Context, srv *http.Server, r chi.Router) error {
+	if files := overview.Files(); files != nil {
+		return addProdRoutes(r, files)
+	}
+	projectRoot, err := project.Root()
+	if err != nil {
+		return err
+	}
+	if *dev {
+		return addDevRoutes(ctx, srv, r, projectRoot)
+	}
+	return addProdRoutes(r, os.DirFS(projectRoot))
+}
+
+func run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *dev && overview.Files() != nil {
+		fmt.Println("Files embedded in the binary: development mode disabled")
+		*dev = false
+	}
+	tls := httpserver.TLS{CertFile: *tlsCert, KeyFile: *tlsKey}
+	useTLS, err := tls.Enabled()
+	if err != nil {
+		return err
+	}
+	chapters, err := lessons.New()
+	if err != nil {
+		return err
+	}
+	lessonsAPI := webapi.NewLessons(chapters)
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	if *logQuery {
+		r.Use(httpserver.AccessLog(slog.New(slog.NewJSONHandler(os.Stdout, nil))))
+	}
+	r.Use(httpserver.SecurityHeaders(useTLS))
+	if *dev {
+		r.Use(middleware.NoCache)
+	}
+	r.Use(lessonsAPI.RedirectNumeric)
+	health := &httpserver.Health{}
+	r.Get("/healthz", health.Healthz)
+	r.Get("/readyz", health.Readyz)
+	srv := &http.Server{
+		Addr:              buildAddr(),
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	srv.RegisterOnShutdown(func() {
+		health.SetReady(false)
 	})
-	addr := buildAddr()
-	fmt.Printf("Listening on %s\n", addr)
-	if err := http.ListenAndServe(addr, r); err != nil {
-		return fmt.Errorf("cannot run HTTP server: %v", err)
+	if err := addAPIRoutes(r, lessonsAPI); err != nil {
+		return err
 	}
+	if err := addFileRoutes(ctx, srv, r); err != nil {
+		return err
+	}
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	fmt.Printf("Listening on %s://%s\n", scheme, srv.Addr)
+	health.SetReady(true)
+	if err := httpserver.ListenAndServe(ctx, srv, tls, *shutdown); err != nil {
+		return err
+	}
+	fmt.Println("Server shut down")
 	return nil
 }
 
 func main() {
+	if worker.IsWorker() {
+		if err := worker.Run(); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
 	flag.Parse()
-	err := run()
-	fmt.Fprint(os.Stderr, err.Error())
-	os.Exit(1)
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,239 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mathml
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var (
+	greekLetters = map[string]string{
+		"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ϵ", "varepsilon": "ε",
+		"zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ", "iota": "ι", "kappa": "κ",
+		"lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "pi": "π", "rho": "ρ", "sigma": "σ",
+		"tau": "τ", "upsilon": "υ", "phi": "ϕ", "varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+		"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+		"Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+	}
+
+	// symbols are identifiers other than letters.
+	symbols = map[string]string{
+		"infty": "∞", "partial": "∂", "nabla": "∇", "ell": "ℓ", "emptyset": "∅", "hbar": "ℏ",
+	}
+
+	operators = map[string]string{
+		"cdot": "⋅", "times": "×", "div": "÷", "pm": "±", "mp": "∓", "ast": "∗", "star": "⋆",
+		"circ": "∘", "odot": "⊙", "otimes": "⊗", "oplus": "⊕",
+		"le": "≤", "leq": "≤", "ge": "≥", "geq": "≥", "ne": "≠", "neq": "≠", "ll": "≪", "gg": "≫",
+		"approx": "≈", "equiv": "≡", "sim": "∼", "simeq": "≃", "propto": "∝",
+		"in": "∈", "notin": "∉", "ni": "∋", "subset": "⊂", "subseteq": "⊆", "supset": "⊃", "supseteq": "⊇",
+		"cup": "∪", "cap": "∩", "setminus": "∖", "wedge": "∧", "land": "∧", "vee": "∨", "lor": "∨", "neg": "¬",
+		"to": "→", "rightarrow": "→", "leftarrow": "←", "gets": "←", "leftrightarrow": "↔",
+		"Rightarrow": "⇒", "implies": "⇒", "Leftarrow": "⇐", "Leftrightarrow": "⇔", "iff": "⇔", "mapsto": "↦",
+		"forall": "∀", "exists": "∃", "top": "⊤", "perp": "⊥", "mid": "∣",
+		"ldots": "…", "dots": "…", "cdots": "⋯", "vdots": "⋮", "ddots": "⋱",
+		"langle": "⟨", "rangle": "⟩", "lfloor": "⌊", "rfloor": "⌋", "lceil": "⌈", "rceil": "⌉",
+		"{": "{", "}": "}", "|": "‖", "_": "_", "$": "$", "%": "%", "&": "&", "#": "#",
+	}
+
+	// largeOperators have their scripts placed under and over them.
+	largeOperators = map[string]string{
+		"sum": "∑", "prod": "∏", "coprod": "∐", "bigcup": "⋃", "bigcap": "⋂",
+	}
+
+	integrals = map[string]string{
+		"int": "∫", "iint": "∬", "iiint": "∭", "oint": "∮",
+	}
+
+	functions = map[string]bool{
+		"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true,
+		"arcsin": true, "arccos": true, "arctan": true, "sinh": true, "cosh": true, "tanh": true,
+		"exp": true, "log": true, "ln": true, "lg": true, "det": true, "dim": true, "ker": true,
+		"deg": true, "arg": true, "gcd": true, "Pr": true,
+	}
+
+	// limitFunctions are functions with their subscript placed under them.
+	limitFunctions = map[string]bool{
+		"lim": true, "max": true, "min": true, "sup": true, "inf": true,
+		"argmax": true, "argmin": true, "limsup": true, "liminf": true,
+	}
+
+	fontVariants = map[string]string{
+		"mathbf": "bold", "boldsymbol": "bold-italic", "mathrm": "normal", "mathit": "italic",
+		"mathbb": "double-struck", "mathcal": "script", "mathfrak": "fraktur", "mathsf": "sans-serif",
+		"mathtt": "monospace",
+	}
+
+	accents = map[string]string{
+		"hat": "^", "widehat": "^", "bar": "¯", "overline": "¯", "vec": "→", "tilde": "~",
+		"widetilde": "~", "dot": "˙", "ddot": "¨",
+	}
+
+	spaces = map[string]string{
+		",": "0.1667em", ":": "0.2222em", ">": "0.2222em", ";": "0.2778em", " ": "0.2778em",
+		"quad": "1em", "qquad": "2em", "!": "-0.1667em",
+	}
+)
+
+func (p *parser) parseCommand(name string) (node, error) {
+	if letter, ok := greekLetters[name]; ok {
+		return p.identifier(letter, unicode.IsUpper([]rune(letter)[0])), nil
+	}
+	if sym, ok := symbols[name]; ok {
+		return p.identifier(sym, true), nil
+	}
+	if op, ok := operators[name]; ok {
+		return operator(op), nil
+	}
+	if op, ok := largeOperators[name]; ok {
+		return node{xml: "<mo largeop=\"true\" movablelimits=\"true\">" + op + "</mo>", limits: true}, nil
+	}
+	if op, ok := integrals[name]; ok {
+		return node{xml: "<mo largeop=\"true\">" + op + "</mo>"}, nil
+	}
+	if functions[name] {
+		return node{xml: "<mi>" + name + "</mi>"}, nil
+	}
+	if limitFunctions[name] {
+		return node{xml: "<mo movablelimits=\"true\">" + name + "</mo>", limits: true}, nil
+	}
+	if variant, ok := fontVariants[name]; ok {
+		return p.parseVariant(variant)
+	}
+	if accent, ok := accents[name]; ok {
+		arg, err := p.parseArg()
+		if err != nil {
+			return node{}, fmt.Errorf("\\%s: %v", name, err)
+		}
+		return node{xml: `<mover accent="true">` + arg + `<mo stretchy="false">` + accent + "</mo></mover>"}, nil
+	}
+	if width, ok := spaces[name]; ok {
+		return node{xml: `<mspace width="` + width + `"></mspace>`}, nil
+	}
+	switch name {
+	case "frac", "dfrac", "tfrac":
+		num, err := p.parseArg()
+		if err != nil {
+			return node{}, fmt.Errorf("\\%s: %v", name, err)
+		}
+		den, err := p.parseArg()
+		if err != nil {
+			return node{}, fmt.Errorf("\\%s: %v", name, err)
+		}
+		return node{xml: "<mfrac>" + num + den + "</mfrac>"}, nil
+	case "sqrt":
+		return p.parseSqrt()
+	case "text", "textrm", "mbox":
+		text, err := p.rawGroup()
+		if err != nil {
+			return node{}, fmt.Errorf("\\%s: %v", name, err)
+		}
+		return node{xml: "<mtext>" + escape(text) + "</mtext>"}, nil
+	case "operatorname":
+		text, err := p.rawGroup()
+		if err != nil {
+			return node{}, fmt.Errorf("\\%s: %v", name, err)
+		}
+		return node{xml: "<mi>" + escape(text) + "</mi>"}, nil
+	case "left":
+		return p.parseLeftRight()
+	}
+	return node{}, fmt.Errorf("unknown command \\%s", name)
+}
+
+func (p *parser) parseVariant(variant string) (node, error) {
+	prev := p.variant
+	p.variant = variant
+	defer func() { p.variant = prev }()
+	arg, err := p.parseArg()
+	if err != nil {
+		return node{}, err
+	}
+	return node{xml: arg}, nil
+}
+
+func (p *parser) parseSqrt() (node, error) {
+	var index []string
+	if tok, ok := p.peek(); ok && tok.kind == tokOther && tok.text == "[" {
+		p.pos++
+		for {
+			tok, ok := p.peek()
+			if !ok {
+				return node{}, fmt.Errorf("\\sqrt: missing ]")
+			}
+			if tok.kind == tokOther && tok.text == "]" {
+				p.pos++
+				break
+			}
+			nd, err := p.parseAtom()
+			if err != nil {
+				return node{}, err
+			}
+			xml, err := p.parseScripts(nd)
+			if err != nil {
+				return node{}, err
+			}
+			index = append(index, xml)
+		}
+	}
+	arg, err := p.parseArg()
+	if err != nil {
+		return node{}, fmt.Errorf("\\sqrt: %v", err)
+	}
+	if len(index) > 0 {
+		return node{xml: "<mroot>" + arg + row(index) + "</mroot>"}, nil
+	}
+	return node{xml: "<msqrt>" + arg + "</msqrt>"}, nil
+}
+
+// delimiter parses the delimiter following \left or \right.
+func (p *parser) delimiter(cmd string) (string, error) {
+	tok, ok := p.next()
+	if !ok {
+		return "", fmt.Errorf("\\%s: missing delimiter", cmd)
+	}
+	switch {
+	case tok.kind == tokOther && tok.text == ".":
+		return "", nil
+	case tok.kind == tokOther:
+		return `<mo fence="true" stretchy="true">` + escape(tok.text) + "</mo>", nil
+	case tok.kind == tokCommand:
+		if op, ok := operators[tok.text]; ok {
+			return `<mo fence="true" stretchy="true">` + escape(op) + "</mo>", nil
+		}
+	}
+	return "", fmt.Errorf("\\%s: invalid delimiter %s", cmd, tok.text)
+}
+
+func (p *parser) parseLeftRight() (node, error) {
+	left, err := p.delimiter("left")
+	if err != nil {
+		return node{}, err
+	}
+	nodes, err := p.parseList()
+	if err != nil {
+		return node{}, err
+	}
+	if tok, ok := p.next(); !ok || tok.kind != tokCommand || tok.text != "right" {
+		return node{}, fmt.Errorf("\\left without \\right")
+	}
+	right, err := p.delimiter("right")
+	if err != nil {
+		return node{}, err
+	}
+	return node{xml: "<mrow>" + left + strings.Join(nodes, "") + right + "</mrow>"}, nil
+}
@@ -0,0 +1,310 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mathml converts TeX math expressions to MathML.
+//
+// Only the subset of TeX commonly used in lessons is supported:
+// scripts, fractions, roots, Greek letters, common operators and
+// functions, font styles, accents, text, spacing and delimiters.
+package mathml
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokLetter tokenKind = iota
+	tokNumber
+	tokCommand
+	tokOpen
+	tokClose
+	tokSup
+	tokSub
+	tokOther
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// pos is the position of the token in the source in runes.
+	pos int
+}
+
+func tokenize(runes []rune) []token {
+	var toks []token
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+		case unicode.IsLetter(r):
+			toks = append(toks, token{tokLetter, string(r), i})
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.') {
+				i++
+			}
+			toks = append(toks, token{tokNumber, string(runes[start : i+1]), start})
+		case r == '\\':
+			start := i + 1
+			for i+1 < len(runes) && unicode.IsLetter(runes[i+1]) {
+				i++
+			}
+			if i+1 == start && i+1 < len(runes) {
+				// Control symbol such as \, or \{.
+				i++
+			}
+			toks = append(toks, token{tokCommand, string(runes[start : i+1]), start - 1})
+		case r == '{':
+			toks = append(toks, token{tokOpen, "{", i})
+		case r == '}':
+			toks = append(toks, token{tokClose, "}", i})
+		case r == '^':
+			toks = append(toks, token{tokSup, "^", i})
+		case r == '_':
+			toks = append(toks, token{tokSub, "_", i})
+		default:
+			toks = append(toks, token{tokOther, string(r), i})
+		}
+	}
+	return toks
+}
+
+// node is the MathML of an expression.
+type node struct {
+	xml string
+	// limits is true if scripts are placed under and over the node.
+	limits bool
+}
+
+type parser struct {
+	src     []rune
+	toks    []token
+	pos     int
+	variant string
+}
+
+// Convert returns the MathML of a TeX math expression.
+// The expression is displayed as a block if display is true.
+func Convert(tex string, display bool) (string, error) {
+	src := []rune(tex)
+	p := &parser{src: src, toks: tokenize(src)}
+	nodes, err := p.parseList()
+	if err != nil {
+		return "", err
+	}
+	if tok, ok := p.peek(); ok {
+		return "", fmt.Errorf("unexpected %s", tok.text)
+	}
+	open := "<math>"
+	if display {
+		open = `<math display="block">`
+	}
+	return open + row(nodes) + "</math>", nil
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.toks) {
+		return token{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+func (p *parser) next() (token, bool) {
+	tok, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return tok, ok
+}
+
+func row(nodes []string) string {
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+	return "<mrow>" + strings.Join(nodes, "") + "</mrow>"
+}
+
+// parseList parses expressions until the end of the source, a closing brace or \right.
+func (p *parser) parseList() ([]string, error) {
+	var nodes []string
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == tokClose || (tok.kind == tokCommand && tok.text == "right") {
+			return nodes, nil
+		}
+		nd, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		xml, err := p.parseScripts(nd)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, xml)
+	}
+}
+
+// parseScripts parses the subscript and superscript following a node.
+func (p *parser) parseScripts(base node) (string, error) {
+	var sub, sup string
+	for {
+		tok, ok := p.peek()
+		if !ok || (tok.kind != tokSub && tok.kind != tokSup) {
+			break
+		}
+		p.pos++
+		arg, err := p.parseArg()
+		if err != nil {
+			return "", err
+		}
+		script := &sup
+		if tok.kind == tokSub {
+			script = &sub
+		}
+		if *script != "" {
+			return "", fmt.Errorf("double %s", tok.text)
+		}
+		*script = arg
+	}
+	under, over, both := "msub", "msup", "msubsup"
+	if base.limits {
+		under, over, both = "munder", "mover", "munderover"
+	}
+	switch {
+	case sub != "" && sup != "":
+		return "<" + both + ">" + base.xml + sub + sup + "</" + both + ">", nil
+	case sub != "":
+		return "<" + under + ">" + base.xml + sub + "</" + under + ">", nil
+	case sup != "":
+		return "<" + over + ">" + base.xml + sup + "</" + over + ">", nil
+	}
+	return base.xml, nil
+}
+
+// parseArg parses the argument of a command or a script: a group or a single atom.
+func (p *parser) parseArg() (string, error) {
+	tok, ok := p.peek()
+	if !ok || tok.kind == tokClose {
+		return "", fmt.Errorf("missing argument")
+	}
+	if tok.kind == tokSub || tok.kind == tokSup {
+		return "", fmt.Errorf("unexpected %s", tok.text)
+	}
+	nd, err := p.parseAtom()
+	if err != nil {
+		return "", err
+	}
+	return nd.xml, nil
+}
+
+// parseGroup parses the content of a group after its opening brace.
+func (p *parser) parseGroup() ([]string, error) {
+	nodes, err := p.parseList()
+	if err != nil {
+		return nil, err
+	}
+	if tok, ok := p.next(); !ok || tok.kind != tokClose {
+		return nil, fmt.Errorf("missing }")
+	}
+	return nodes, nil
+}
+
+// rawGroup returns the source of a group as text.
+func (p *parser) rawGroup() (string, error) {
+	open, ok := p.next()
+	if !ok || open.kind != tokOpen {
+		return "", fmt.Errorf("missing {")
+	}
+	for depth := 1; ; {
+		tok, ok := p.next()
+		if !ok {
+			return "", fmt.Errorf("missing }")
+		}
+		switch tok.kind {
+		case tokOpen:
+			depth++
+		case tokClose:
+			depth--
+		}
+		if depth == 0 {
+			return string(p.src[open.pos+1 : tok.pos]), nil
+		}
+	}
+}
+
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
+
+func (p *parser) identifier(text string, upright bool) node {
+	variant := p.variant
+	if variant == "" && upright {
+		variant = "normal"
+	}
+	if variant == "" {
+		return node{xml: "<mi>" + escape(text) + "</mi>"}
+	}
+	return node{xml: `<mi mathvariant="` + variant + `">` + escape(text) + "</mi>"}
+}
+
+func operator(text string) node {
+	return node{xml: "<mo>" + escape(text) + "</mo>"}
+}
+
+var otherOperators = map[string]string{
+	"-":  "−",
+	"*":  "∗",
+	"'":  "′",
+	"\"": "″",
+}
+
+func (p *parser) parseAtom() (node, error) {
+	tok, _ := p.next()
+	switch tok.kind {
+	case tokLetter:
+		return p.identifier(tok.text, false), nil
+	case tokNumber:
+		if p.variant != "" {
+			return node{xml: `<mn mathvariant="` + p.variant + `">` + tok.text + "</mn>"}, nil
+		}
+		return node{xml: "<mn>" + tok.text + "</mn>"}, nil
+	case tokOpen:
+		nodes, err := p.parseGroup()
+		if err != nil {
+			return node{}, err
+		}
+		if len(nodes) == 0 {
+			return node{xml: "<mrow></mrow>"}, nil
+		}
+		return node{xml: row(nodes)}, nil
+	case tokSub, tokSup:
+		// Script without base.
+		p.pos--
+		return node{xml: "<mrow></mrow>"}, nil
+	case tokCommand:
+		return p.parseCommand(tok.text)
+	}
+	switch tok.text {
+	case "&", "#", "~", "%":
+		return node{}, fmt.Errorf("unsupported character %s", tok.text)
+	}
+	if op, ok := otherOperators[tok.text]; ok {
+		return operator(op), nil
+	}
+	return operator(tok.text), nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mathml_test
+
+import (
+	"testing"
+
+	"github.com/gx-org/gx-org/internal/mathml"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		tex     string
+		display bool
+		want    string
+		err     bool
+	}{
+		{
+			tex:  "x^2 + 1",
+			want: "<math><mrow><msup><mi>x</mi><mn>2</mn></msup><mo>+</mo><mn>1</mn></mrow></math>",
+		},
+		{
+			tex:  `\frac{a}{b_i}`,
+			want: "<math><mfrac><mi>a</mi><msub><mi>b</mi><mi>i</mi></msub></mfrac></math>",
+		},
+		{
+			tex:     `\sum_{i=1}^n x_i`,
+			display: true,
+			want:    `<math display="block"><mrow><munderover><mo largeop="true" movablelimits="true">∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></munderover><msub><mi>x</mi><mi>i</mi></msub></mrow></math>`,
+		},
+		{
+			tex:  `\sigma(\mathbf{W} x) \le \Sigma`,
+			want: `<math><mrow><mi>σ</mi><mo>(</mo><mi mathvariant="bold">W</mi><mi>x</mi><mo>)</mo><mo>≤</mo><mi mathvariant="normal">Σ</mi></mrow></math>`,
+		},
+		{
+			tex:  `\sqrt[3]{x} - \sqrt{2.5}`,
+			want: "<math><mrow><mroot><mi>x</mi><mn>3</mn></mroot><mo>−</mo><msqrt><mn>2.5</mn></msqrt></mrow></math>",
+		},
+		{
+			tex:  `\left( \hat{y} \right) \text{if } a<b`,
+			want: `<math><mrow><mrow><mo fence="true" stretchy="true">(</mo><mover accent="true"><mi>y</mi><mo stretchy="false">^</mo></mover><mo fence="true" stretchy="true">)</mo></mrow><mtext>if </mtext><mi>a</mi><mo>&lt;</mo><mi>b</mi></mrow></math>`,
+		},
+		{tex: `\unknown`, err: true},
+		{tex: `\frac{a}`, err: true},
+		{tex: `{x`, err: true},
+		{tex: `x}`, err: true},
+		{tex: `x^2^3`, err: true},
+		{tex: `\left( x`, err: true},
+		{tex: `a & b`, err: true},
+	}
+	for i, test := range tests {
+		got, err := mathml.Convert(test.tex, test.display)
+		if test.err {
+			if err == nil {
+				t.Errorf("test %d: expected an error for %q but got %s", i, test.tex, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: %q: %v", i, test.tex, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("test %d: unexpected MathML for %q:\ngot:  %s\nwant: %s", i, test.tex, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdtext
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+type (
+	// Directive is a fenced block whose info string starts with TagPrefix,
+	// followed by key=value attributes (e.g. overview:code file=main.gx highlight=3-5).
+	Directive struct {
+		// Tag of the block (e.g. overview:code).
+		Tag string
+		// Attrs are the attributes following the tag.
+		Attrs map[string]string
+		// Code is the content of the block.
+		Code string
+		// Line of the opening fence of the block or 0 if the line is unknown.
+		Line int
+	}
+
+	// DirectiveHandler parses and validates a directive and stores its data in MDText.
+	// Errors are reported at the line of the block and need to name the directive.
+	DirectiveHandler func(mdt *MDText, dir *Directive) error
+
+	// CodeFile is the code of a block tagged with a file attribute.
+	CodeFile struct {
+		// Name of the file or an empty string if the block has no file attribute.
+		Name string
+		Code string
+		// Highlight are the lines to highlight in the code.
+		Highlight []LineRange
+		// Line of the opening fence of the block or 0 if the line is unknown.
+		Line int
+	}
+
+	// LineRange is a range of lines, starting from 1, including the last line.
+	LineRange struct {
+		Start, End int
+	}
+
+	// FuncArgs are the values of the arguments of a function, one per line in an args block.
+	FuncArgs struct {
+		// Func is the name of the function or an empty string if the block has no func attribute.
+		Func   string
+		Values []string
+		// Line of the args block in the markdown source.
+		Line int
+	}
+)
+
+// Registry maps the tags of directives to their handler.
+// A registry is not safe for concurrent use while handlers are registered.
+type Registry struct {
+	handlers map[string]DirectiveHandler
+}
+
+// defaultRegistry is the registry used when no registry is given to the parser.
+// It is never modified.
+var defaultRegistry = NewRegistry()
+
+// NewRegistry returns a registry with the handlers of the directives of this package.
+func NewRegistry() *Registry {
+	return &Registry{handlers: map[string]DirectiveHandler{
+		CodeTag:     parseCodeDirective,
+		SolutionTag: parseSolutionDirective,
+		OutputTag:   parseOutputDirective,
+		TestTag:     parseTestDirective,
+		ArgsTag:     parseArgsDirective,
+	}}
+}
+
+// Register registers the handler of the blocks tagged with TagPrefix+kind.
+// Handlers can store their data in MDText.Data.
+func (r *Registry) Register(kind string, handler DirectiveHandler) error {
+	tag := TagPrefix + kind
+	if _, ok := r.handlers[tag]; ok {
+		return fmt.Errorf("directive %s already registered", tag)
+	}
+	r.handlers[tag] = handler
+	return nil
+}
+
+// WithRegistry parses directives with the handlers of a registry instead of the default ones.
+func WithRegistry(r *Registry) Option {
+	return func(opts *options) {
+		opts.registry = r
+	}
+}
+
+// parseDirective parses the info string and the content of a block tagged with TagPrefix.
+func parseDirective(info, code string, line int) (*Directive, error) {
+	fields := strings.Fields(info)
+	dir := &Directive{Tag: fields[0], Attrs: make(map[string]string), Code: code, Line: line}
+	for _, field := range fields[1:] {
+		key, value, found := strings.Cut(field, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("%s: invalid attribute %q: want key=value", dir.Tag, field)
+		}
+		if _, ok := dir.Attrs[key]; ok {
+			return nil, fmt.Errorf("%s: attribute %s specified more than once", dir.Tag, key)
+		}
+		dir.Attrs[key] = value
+	}
+	return dir, nil
+}
+
+func processDirectives(reg *Registry, mdt *MDText, lines map[ast.Node]int, blocks *[]*ast.CodeBlock, err **Error) func(node *ast.CodeBlock) ast.WalkStatus {
+	return func(node *ast.CodeBlock) ast.WalkStatus {
+		info := string(node.Info)
+		if !strings.HasPrefix(info, TagPrefix) {
+			return ast.GoToNext
+		}
+		*blocks = append(*blocks, node)
+		line := lines[node]
+		dir, dirErr := parseDirective(info, string(node.Literal), line)
+		if dirErr != nil {
+			*err = &Error{Line: line, Err: dirErr}
+			return ast.Terminate
+		}
+		handler, ok := reg.handlers[dir.Tag]
+		if !ok {
+			*err = Errorf("", line, "unknown directive %s", dir.Tag)
+			return ast.Terminate
+		}
+		if dirErr := handler(mdt, dir); dirErr != nil {
+			*err = &Error{Line: line, Err: dirErr}
+			return ast.Terminate
+		}
+		return ast.GoToNext
+	}
+}
+
+// CheckDuplicate returns an error if a block with the same key has already been parsed.
+// Handlers use the key to identify the blocks which can only be specified once.
+func (mdt *MDText) CheckDuplicate(key string, dir *Directive) error {
+	if line, ok := mdt.blockLines[key]; ok {
+		return fmt.Errorf("duplicate block %s: the block is already specified%s", key, atLine(line))
+	}
+	mdt.blockLines[key] = dir.Line
+	return nil
+}
+
+// checkAttrs returns an error if the directive has an attribute not in allowed.
+func (dir *Directive) checkAttrs(allowed ...string) error {
+	for key := range dir.Attrs {
+		if !slices.Contains(allowed, key) {
+			return fmt.Errorf("%s: unknown attribute %s", dir.Tag, key)
+		}
+	}
+	return nil
+}
+
+// Contains returns true if a line is in the range.
+func (r LineRange) Contains(line int) bool {
+	return r.Start <= line && line <= r.End
+}
+
+// parseLineRanges parses comma-separated line numbers or ranges of lines (e.g. 3-5,8).
+func parseLineRanges(s string, numLines int) ([]LineRange, error) {
+	var ranges []LineRange
+	for _, part := range strings.Split(s, ",") {
+		start, end, isRange := strings.Cut(part, "-")
+		var rng LineRange
+		var err error
+		if rng.Start, err = strconv.Atoi(start); err != nil {
+			return nil, fmt.Errorf("invalid line %q", start)
+		}
+		rng.End = rng.Start
+		if isRange {
+			if rng.End, err = strconv.Atoi(end); err != nil {
+				return nil, fmt.Errorf("invalid line %q", end)
+			}
+		}
+		if rng.Start < 1 || rng.End < rng.Start || rng.End > numLines {
+			return nil, fmt.Errorf("invalid line range %s: the code has %d lines", part, numLines)
+		}
+		ranges = append(ranges, rng)
+	}
+	return ranges, nil
+}
+
+func codeFile(dir *Directive) (CodeFile, error) {
+	if err := dir.checkAttrs("file", "highlight"); err != nil {
+		return CodeFile{}, err
+	}
+	file := CodeFile{Name: dir.Attrs["file"], Code: dir.Code, Line: dir.Line}
+	if lines, ok := dir.Attrs["highlight"]; ok {
+		var err error
+		if file.Highlight, err = parseLineRanges(lines, strings.Count(dir.Code, "\n")); err != nil {
+			return CodeFile{}, fmt.Errorf("%s: highlight: %v", dir.Tag, err)
+		}
+	}
+	return file, nil
+}
+
+// attrKey identifies the blocks with the same tag and the same value for an attribute.
+func attrKey(dir *Directive, attr string) string {
+	if value := dir.Attrs[attr]; value != "" {
+		return dir.Tag + " " + attr + "=" + value
+	}
+	return dir.Tag
+}
+
+func parseCodeDirective(mdt *MDText, dir *Directive) error {
+	file, err := codeFile(dir)
+	if err != nil {
+		return err
+	}
+	if err := mdt.CheckDuplicate(attrKey(dir, "file"), dir); err != nil {
+		return err
+	}
+	mdt.Sources = append(mdt.Sources, file)
+	return nil
+}
+
+func parseSolutionDirective(mdt *MDText, dir *Directive) error {
+	file, err := codeFile(dir)
+	if err != nil {
+		return err
+	}
+	if err := mdt.CheckDuplicate(attrKey(dir, "file"), dir); err != nil {
+		return err
+	}
+	mdt.Solution = append(mdt.Solution, file)
+	return nil
+}
+
+func parseOutputDirective(mdt *MDText, dir *Directive) error {
+	if err := dir.checkAttrs(); err != nil {
+		return err
+	}
+	if err := mdt.CheckDuplicate(dir.Tag, dir); err != nil {
+		return err
+	}
+	mdt.Output = dir.Code
+	return nil
+}
+
+func parseTestDirective(mdt *MDText, dir *Directive) error {
+	if err := dir.checkAttrs(); err != nil {
+		return err
+	}
+	if err := mdt.CheckDuplicate(dir.Tag, dir); err != nil {
+		return err
+	}
+	mdt.Test = dir.Code
+	return nil
+}
+
+func parseArgsDirective(mdt *MDText, dir *Directive) error {
+	if err := dir.checkAttrs("func"); err != nil {
+		return err
+	}
+	if err := mdt.CheckDuplicate(attrKey(dir, "func"), dir); err != nil {
+		return err
+	}
+	args := FuncArgs{Func: dir.Attrs["func"], Values: []string{}, Line: dir.Line}
+	for line := range strings.Lines(dir.Code) {
+		if line = strings.TrimSpace(line); line != "" {
+			args.Values = append(args.Values, line)
+		}
+	}
+	mdt.Args = append(mdt.Args, args)
+	return nil
+}
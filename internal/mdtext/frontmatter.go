@@ -0,0 +1,74 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdtext
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontMatterDelim delimits the front matter at the start of a markdown source.
+const frontMatterDelim = "---"
+
+// FrontMatter is the optional metadata of a markdown source.
+// It is written in YAML between two --- lines at the start of the source.
+type FrontMatter struct {
+	Title         string   `yaml:"title" json:"title,omitempty"`
+	Slug          string   `yaml:"slug" json:"slug,omitempty"`
+	Tags          []string `yaml:"tags" json:"tags,omitempty"`
+	Difficulty    string   `yaml:"difficulty" json:"difficulty,omitempty"`
+	Minutes       int      `yaml:"estimated_minutes" json:"estimated_minutes,omitempty"`
+	Prerequisites []string `yaml:"prerequisites" json:"prerequisites,omitempty"`
+	MinGXVersion  string   `yaml:"min_gx_version" json:"min_gx_version,omitempty"`
+	// Packages is a folder of GX packages importable from the code.
+	Packages string `yaml:"packages" json:"packages,omitempty"`
+}
+
+// splitFrontMatter returns the front matter of a markdown source and the markdown without it.
+// The lines of the front matter are replaced by empty lines
+// such that line numbers in the markdown do not change.
+func splitFrontMatter(src []byte) (front, md []byte, err error) {
+	lines := bytes.SplitAfter(src, []byte("\n"))
+	if len(lines) == 0 || string(bytes.TrimSpace(lines[0])) != frontMatterDelim {
+		return nil, src, nil
+	}
+	for i := 1; i < len(lines); i++ {
+		if string(bytes.TrimSpace(lines[i])) != frontMatterDelim {
+			continue
+		}
+		front = bytes.Join(lines[1:i], nil)
+		md = append(bytes.Repeat([]byte("\n"), i+1), bytes.Join(lines[i+1:], nil)...)
+		return front, md, nil
+	}
+	return nil, nil, fmt.Errorf("front matter not closed by %s", frontMatterDelim)
+}
+
+func parseFrontMatter(src []byte) (*FrontMatter, error) {
+	fm := &FrontMatter{}
+	if len(bytes.TrimSpace(src)) == 0 {
+		return fm, nil
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(src))
+	dec.KnownFields(true)
+	if err := dec.Decode(fm); err != nil {
+		return nil, fmt.Errorf("invalid front matter: %v", err)
+	}
+	if fm.Minutes < 0 {
+		return nil, fmt.Errorf("invalid front matter: negative estimated_minutes %d", fm.Minutes)
+	}
+	return fm, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdtext
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// LessonScheme is the scheme of links to other lessons given their slug (e.g. [arrays](lesson:arrays)).
+const LessonScheme = "lesson:"
+
+// LessonAttr is the HTML attribute storing the slug of the lesson targeted by a link.
+const LessonAttr = "data-lesson"
+
+// LessonURL returns the URL of the page displaying a lesson given its slug.
+func LessonURL(slug string) string {
+	return "./index.html?lesson=" + url.QueryEscape(slug)
+}
+
+// processLessonLinks rewrites links to lessons so that they point to the lesson page
+// and records the slugs of the lessons in the order in which they appear.
+func processLessonLinks(slugs *[]string) func(node *ast.Link) ast.WalkStatus {
+	return func(node *ast.Link) ast.WalkStatus {
+		slug, found := strings.CutPrefix(string(node.Destination), LessonScheme)
+		if !found {
+			return ast.GoToNext
+		}
+		*slugs = append(*slugs, slug)
+		node.Destination = []byte(LessonURL(slug))
+		node.AdditionalAttributes = append(node.AdditionalAttributes, LessonAttr+`="`+escapeAttr(slug)+`"`)
+		return ast.GoToNext
+	}
+}
+
+func escapeAttr(s string) string {
+	return strings.NewReplacer(`&`, "&amp;", `"`, "&quot;", `<`, "&lt;", `>`, "&gt;").Replace(s)
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdtext
+
+import (
+	"io"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gx-org/gx-org/internal/mathml"
+)
+
+// convertMath converts the inline ($...$) and display ($$...$$) math of a document to MathML.
+func convertMath(doc ast.Node, src []byte) (map[ast.Node]string, *Error) {
+	var nodes []ast.Node
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		switch node.(type) {
+		case *ast.Math, *ast.MathBlock:
+			if entering {
+				nodes = append(nodes, node)
+			}
+		}
+		return ast.GoToNext
+	})
+	lines := make(map[ast.Node]int)
+	matchLines(lines, nodes, mathSource, func(s string) []int { return substringLines(src, s) })
+	mathML := make(map[ast.Node]string)
+	for _, node := range nodes {
+		tex, display := texOf(node)
+		out, err := mathml.Convert(string(tex), display)
+		if err != nil {
+			return nil, Errorf("", lines[node], "cannot convert math %q: %v", tex, err)
+		}
+		mathML[node] = out
+	}
+	return mathML, nil
+}
+
+// texOf returns the TeX of a math node and whether the math is displayed.
+func texOf(node ast.Node) ([]byte, bool) {
+	if block, ok := node.(*ast.MathBlock); ok {
+		return block.Literal, true
+	}
+	return node.(*ast.Math).Literal, false
+}
+
+// mathSource returns the inline math of a node with its delimiters or the TeX of display math.
+func mathSource(node ast.Node) string {
+	tex, display := texOf(node)
+	if display {
+		return string(tex)
+	}
+	return "$" + string(tex) + "$"
+}
+
+// renderMath returns a renderer hook writing the MathML of math nodes.
+func renderMath(mathML map[ast.Node]string) html.RenderNodeFunc {
+	return func(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+		out, ok := mathML[node]
+		if !ok {
+			return ast.GoToNext, false
+		}
+		if entering {
+			if _, isBlock := node.(*ast.MathBlock); isBlock {
+				out += "\n"
+			}
+			io.WriteString(w, out)
+		}
+		return ast.GoToNext, true
+	}
+}
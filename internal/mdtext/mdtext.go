@@ -1,6 +1,8 @@
 package mdtext
 
 import (
+	"fmt"
+	"io"
 	"strings"
 
 	"github.com/gomarkdown/markdown"
@@ -11,23 +13,30 @@ import (
 
 const TagPrefix = "overview:"
 
-func processCodeWithGXTags(m map[string]*ast.CodeBlock) func(node *ast.CodeBlock) ast.WalkStatus {
-	return func(node *ast.CodeBlock) ast.WalkStatus {
-		codeTag := string(node.Info)
-		if !strings.HasPrefix(codeTag, TagPrefix) {
-			return ast.GoToNext
-		}
-		m[codeTag] = node
-		return ast.GoToNext
-	}
-}
+const (
+	// CodeTag is the tag of the blocks with the GX code of a lesson.
+	// The name of the file is given by the file attribute (e.g. overview:code file=model.gx)
+	// and the lines to highlight by the highlight attribute (e.g. highlight=3-5,8).
+	CodeTag = TagPrefix + "code"
+	// OutputTag is the tag of the block with the expected output of the GX code of a lesson.
+	OutputTag = TagPrefix + "output"
+	// TestTag is the tag of the block with the GX test functions of a lesson.
+	// Tests are compiled with the code of the lesson but are not displayed.
+	TestTag = TagPrefix + "test"
+	// SolutionTag is the tag of the blocks with the solution of the exercise of a lesson.
+	// It accepts the same attributes as CodeTag.
+	SolutionTag = TagPrefix + "solution"
+	// ArgsTag is the tag of the block with the arguments passed to a function, one per line.
+	// The name of the function is given by the func attribute (e.g. overview:args func=Main).
+	ArgsTag = TagPrefix + "args"
+)
 
-func titleNode(heading **ast.Heading) func(node *ast.Heading) ast.WalkStatus {
+func titleNodes(headings *[]*ast.Heading) func(node *ast.Heading) ast.WalkStatus {
 	return func(node *ast.Heading) ast.WalkStatus {
 		if node.Level != 1 {
 			return ast.GoToNext
 		}
-		*heading = node
+		*headings = append(*headings, node)
 		return ast.GoToNext
 	}
 }
@@ -46,31 +55,175 @@ func walk[T ast.Node](process func(T) ast.WalkStatus) ast.NodeVisitorFunc {
 }
 
 type MDText struct {
-	TitleHTML string
-	Code      map[string]string
-	HTML      string
+	FrontMatter FrontMatter
+	TitleHTML   string
+	Title       string
+	// TitleLine is the line of the title in the source or 0 if the line is unknown.
+	TitleLine int
+	// Sources are the CodeTag blocks in the order in which they appear in the source.
+	Sources []CodeFile
+	// Solution are the SolutionTag blocks in the order in which they appear in the source.
+	Solution []CodeFile
+	// Output is the content of the OutputTag block.
+	Output string
+	// Test is the content of the TestTag block.
+	Test string
+	// Args are the ArgsTag blocks in the order in which they appear in the source.
+	Args []FuncArgs
+	// Data is set by the handlers registered with Registry.Register.
+	Data map[string]any
+	// LessonLinks are the slugs of the lessons linked from the text with the lesson: scheme.
+	LessonLinks []string
+	// Snippets are the sources of the GX code blocks displayed in the text.
+	Snippets []string
+	HTML     string
+
+	// blockLines are the lines of the blocks given a key identifying the blocks.
+	blockLines map[string]int
+}
+
+// plainText returns the text of a node without markup.
+func plainText(node ast.Node) string {
+	var text strings.Builder
+	ast.WalkFunc(node, func(node ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.GoToNext
+		}
+		if leaf := node.AsLeaf(); leaf != nil {
+			text.Write(leaf.Literal)
+		}
+		return ast.GoToNext
+	})
+	return text.String()
+}
+
+// renderHooks returns a renderer hook calling hooks in order until one of them renders the node.
+func renderHooks(hooks ...html.RenderNodeFunc) html.RenderNodeFunc {
+	return func(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+		for _, hook := range hooks {
+			if status, handled := hook(w, node, entering); handled {
+				return status, true
+			}
+		}
+		return ast.GoToNext, false
+	}
+}
+
+type (
+	// Option configures the parser.
+	Option func(*options)
+
+	options struct {
+		trustedHTML bool
+		registry    *Registry
+	}
+)
+
+// TrustedHTML keeps all the HTML of the source instead of sanitizing it.
+// Only use it for sources written by trusted authors.
+func TrustedHTML() Option {
+	return func(opts *options) {
+		opts.trustedHTML = true
+	}
+}
+
+// Parse parses a markdown source.
+// The HTML is sanitized with Sanitize unless the TrustedHTML option is given.
+// Errors are of type *Error with the line of the error.
+func Parse(src []byte, opts ...Option) (*MDText, error) {
+	return ParseFile("", src, opts...)
 }
 
-func Parse(src []byte) *MDText {
+// ParseFile parses the markdown source of a file.
+// Errors are of type *Error with the name of the file and the line of the error.
+func ParseFile(name string, src []byte, opts ...Option) (*MDText, error) {
+	parseOpts := options{registry: defaultRegistry}
+	for _, opt := range opts {
+		opt(&parseOpts)
+	}
+	mdt, err := parse(src, &parseOpts)
+	if err != nil {
+		err.File = name
+		return nil, err
+	}
+	return mdt, nil
+}
+
+func parse(src []byte, opts *options) (*MDText, *Error) {
+	front, src, err := splitFrontMatter(src)
+	if err != nil {
+		return nil, &Error{Line: 1, Err: err}
+	}
+	frontMatter, err := parseFrontMatter(front)
+	if err != nil {
+		return nil, &Error{Line: 1, Err: err}
+	}
 	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock
 	p := parser.NewWithExtensions(extensions)
 	doc := p.Parse(src)
-	codeBlockTags := make(map[string]*ast.CodeBlock)
-	ast.Walk(doc, walk(processCodeWithGXTags(codeBlockTags)))
-	mdt := &MDText{Code: make(map[string]string)}
-	for tag, codeBlock := range codeBlockTags {
-		mdt.Code[tag] = string(codeBlock.Literal)
-		ast.RemoveFromTree(codeBlock)
+	lines := nodeLines(doc, src)
+	mdt := &MDText{FrontMatter: *frontMatter, Data: make(map[string]any), blockLines: make(map[string]int)}
+	var blocks []*ast.CodeBlock
+	var dirErr *Error
+	ast.Walk(doc, walk(processDirectives(opts.registry, mdt, lines, &blocks, &dirErr)))
+	if dirErr != nil {
+		return nil, dirErr
+	}
+	for _, block := range blocks {
+		ast.RemoveFromTree(block)
+	}
+	ast.Walk(doc, walk(processLessonLinks(&mdt.LessonLinks)))
+	mathML, mathErr := convertMath(doc, src)
+	if mathErr != nil {
+		return nil, mathErr
 	}
+	snippets := make(map[ast.Node]int)
+	ast.Walk(doc, walk(processSnippets(snippets, &mdt.Snippets)))
 	htmlFlags := html.CommonFlags | html.HrefTargetBlank
-	opts := html.RendererOptions{Flags: htmlFlags}
-	renderer := html.NewRenderer(opts)
-	var title *ast.Heading
-	ast.Walk(doc, walk(titleNode(&title)))
-	if title != nil {
+	renderer := html.NewRenderer(html.RendererOptions{
+		Flags:          htmlFlags,
+		RenderNodeHook: renderHooks(renderMath(mathML), renderSnippets(snippets)),
+	})
+	var titles []*ast.Heading
+	ast.Walk(doc, walk(titleNodes(&titles)))
+	if len(titles) > 1 {
+		return nil, Errorf("", lines[titles[1]], "duplicate title %q: the title is already specified%s", plainText(titles[1]), atLine(lines[titles[0]]))
+	}
+	if len(titles) == 1 {
+		title := titles[0]
 		mdt.TitleHTML = string(markdown.Render(title, renderer))
+		mdt.Title = plainText(title)
+		mdt.TitleLine = lines[title]
 		ast.RemoveFromTree(title)
 	}
 	mdt.HTML = string(markdown.Render(doc, renderer))
-	return mdt
+	if !opts.trustedHTML {
+		mdt.TitleHTML = Sanitize(mdt.TitleHTML)
+		mdt.HTML = Sanitize(mdt.HTML)
+	}
+	return mdt, nil
+}
+
+// ReplaceCode returns the markdown source in which the content
+// of the fenced block tagged with tag is replaced by code.
+func ReplaceCode(src []byte, tag, code string) ([]byte, error) {
+	lines := sourceLines(src)
+	for i, line := range lines {
+		fence, info, ok := openingFence(line)
+		if fields := strings.Fields(info); !ok || len(fields) == 0 || fields[0] != tag {
+			continue
+		}
+		for j := i + 1; j < len(lines); j++ {
+			if strings.TrimSpace(lines[j]) != fence {
+				continue
+			}
+			if code != "" && !strings.HasSuffix(code, "\n") {
+				code += "\n"
+			}
+			out := strings.Join(lines[:i+1], "") + code + strings.Join(lines[j:], "")
+			return []byte(out), nil
+		}
+		return nil, fmt.Errorf("block %s is not closed", tag)
+	}
+	return nil, fmt.Errorf("no block %s", tag)
 }
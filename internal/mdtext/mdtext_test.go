@@ -5,15 +5,36 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/gx-org/gx-org/internal/mdtext"
 )
 
+// blockCode returns the content of the first block with a given tag.
+func blockCode(mdt *mdtext.MDText, tag string) string {
+	switch tag {
+	case mdtext.CodeTag:
+		if len(mdt.Sources) > 0 {
+			return mdt.Sources[0].Code
+		}
+	case mdtext.SolutionTag:
+		if len(mdt.Solution) > 0 {
+			return mdt.Solution[0].Code
+		}
+	case mdtext.OutputTag:
+		return mdt.Output
+	case mdtext.TestTag:
+		return mdt.Test
+	}
+	return ""
+}
+
 func TestParse(t *testing.T) {
 	tests := []struct {
-		wantHTML  string
-		wantTitle string
-		md        string
-		code      map[string]string
+		wantHTML      string
+		wantTitle     string
+		wantTitleText string
+		md            string
+		code          map[string]string
 	}{
 		{ /*Empty source*/ },
 		{
@@ -29,6 +50,24 @@ some code
 			},
 			wantTitle: `<h1 id="title-1">Title 1</h1>
 `,
+			wantTitleText: "Title 1",
+			wantHTML: `<p>Some text</p>
+`,
+		},
+		{
+			md: "# Title with `code`\n",
+			wantTitle: `<h1 id="title-with-code">Title with <code>code</code></h1>
+`,
+			wantTitleText: "Title with code",
+		},
+		{
+			md: "Some text\n",
+			code: map[string]string{
+				mdtext.CodeTag:     "some code\n",
+				mdtext.OutputTag:   "[1 2]\n",
+				mdtext.TestTag:     "func TestMain() bool {\n\treturn true\n}\n",
+				mdtext.SolutionTag: "solution code\n",
+			},
 			wantHTML: `<p>Some text</p>
 `,
 		},
@@ -40,18 +79,430 @@ some code
 		for tag, code := range test.code {
 			mdSrc.WriteString(fmt.Sprintf("```%s\n%s```\n", tag, code))
 		}
-		mdText := mdtext.Parse([]byte(mdSrc.String()))
+		mdText, err := mdtext.Parse([]byte(mdSrc.String()))
+		if err != nil {
+			t.Errorf("test %d: %v", i, err)
+			continue
+		}
 		if mdText.TitleHTML != test.wantTitle {
 			t.Errorf("unexpected title in test %d:\ngot:\n%s\nwant:\n%s\n", i, mdText.TitleHTML, test.wantTitle)
 		}
+		if mdText.Title != test.wantTitleText {
+			t.Errorf("unexpected title text in test %d: got %q but want %q", i, mdText.Title, test.wantTitleText)
+		}
 		if mdText.HTML != test.wantHTML {
 			t.Errorf("unexpected HTML in test %d:\ngot:\n%s\nwant:\n%s\n", i, mdText.HTML, test.wantHTML)
 		}
 		for tag, codeWant := range test.code {
-			codeGot := mdText.Code[tag]
+			codeGot := blockCode(mdText, tag)
 			if codeGot != codeWant {
 				t.Errorf("unexpected GX code for tag %s in test %d:\ngot:\n%s\nwant:\n%s\n", tag, i, codeGot, codeWant)
 			}
 		}
 	}
 }
+
+func TestFrontMatter(t *testing.T) {
+	tests := []struct {
+		md       string
+		want     mdtext.FrontMatter
+		wantHTML string
+		err      bool
+	}{
+		{
+			md:       "Some text\n",
+			wantHTML: "<p>Some text</p>\n",
+		},
+		{
+			md:       "---\n---\nSome text\n",
+			wantHTML: "<p>Some text</p>\n",
+		},
+		{
+			md: `---
+title: Arrays
+slug: arrays
+tags: [arrays, types]
+difficulty: beginner
+estimated_minutes: 5
+prerequisites:
+  - welcome
+min_gx_version: v0.1.0
+---
+Some text
+`,
+			want: mdtext.FrontMatter{
+				Title:         "Arrays",
+				Slug:          "arrays",
+				Tags:          []string{"arrays", "types"},
+				Difficulty:    "beginner",
+				Minutes:       5,
+				Prerequisites: []string{"welcome"},
+				MinGXVersion:  "v0.1.0",
+			},
+			wantHTML: "<p>Some text</p>\n",
+		},
+		{
+			md:  "---\ntitle: Arrays\n",
+			err: true,
+		},
+		{
+			md:  "---\nunknown: field\n---\n",
+			err: true,
+		},
+		{
+			md:  "---\nestimated_minutes: -1\n---\n",
+			err: true,
+		},
+	}
+	for i, test := range tests {
+		mdText, err := mdtext.Parse([]byte(test.md))
+		if test.err {
+			if err == nil {
+				t.Errorf("test %d: expected an error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: %v", i, err)
+			continue
+		}
+		if diff := cmp.Diff(test.want, mdText.FrontMatter); diff != "" {
+			t.Errorf("test %d: unexpected front matter (-want +got):\n%s", i, diff)
+		}
+		if mdText.HTML != test.wantHTML {
+			t.Errorf("test %d: unexpected HTML:\ngot:\n%s\nwant:\n%s", i, mdText.HTML, test.wantHTML)
+		}
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	const md = "```overview:code file=model.gx highlight=1\nmodel\n```\n\n```overview:output\n[1 2]\n```\n\n```overview:code\nmain\nline 2\nline 3\n```\n\n```overview:solution file=model.gx\nsolution\n```\n\n```overview:args func=Add\n[1, 2]\n\n3\n```\n"
+	mdText, err := mdtext.Parse([]byte(md))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSources := []mdtext.CodeFile{
+		{Name: "model.gx", Code: "model\n", Highlight: []mdtext.LineRange{{Start: 1, End: 1}}, Line: 1},
+		{Code: "main\nline 2\nline 3\n", Line: 9},
+	}
+	if diff := cmp.Diff(wantSources, mdText.Sources); diff != "" {
+		t.Errorf("unexpected code files (-want +got):\n%s", diff)
+	}
+	wantSolution := []mdtext.CodeFile{{Name: "model.gx", Code: "solution\n", Line: 15}}
+	if diff := cmp.Diff(wantSolution, mdText.Solution); diff != "" {
+		t.Errorf("unexpected solution files (-want +got):\n%s", diff)
+	}
+	wantArgs := []mdtext.FuncArgs{{Func: "Add", Values: []string{"[1, 2]", "3"}, Line: 19}}
+	if diff := cmp.Diff(wantArgs, mdText.Args); diff != "" {
+		t.Errorf("unexpected arguments (-want +got):\n%s", diff)
+	}
+	if mdText.Output != "[1 2]\n" {
+		t.Errorf("unexpected output %q", mdText.Output)
+	}
+}
+
+func TestDirectiveErrors(t *testing.T) {
+	tests := []string{
+		"```overview:unknown\ncode\n```\n",
+		"```overview:code files=main.gx\ncode\n```\n",
+		"```overview:code file\ncode\n```\n",
+		"```overview:code file=a.gx file=b.gx\ncode\n```\n",
+		"```overview:code highlight=2\ncode\n```\n",
+		"```overview:code highlight=2-1\ncode\nline 2\n```\n",
+		"```overview:output file=main.gx\n[1 2]\n```\n",
+	}
+	for i, md := range tests {
+		if _, err := mdtext.Parse([]byte(md)); err == nil {
+			t.Errorf("test %d: expected an error for:\n%s", i, md)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		md   string
+		want string
+	}{
+		{
+			md:   "---\ntitle: A\n---\n# Title\n\nSome text\n\n# Other title\n",
+			want: `lesson.md:8: duplicate title "Other title": the title is already specified at line 4`,
+		},
+		{
+			md:   "Title\n=====\n\n# Other title\n",
+			want: `lesson.md:4: duplicate title "Other title": the title is already specified`,
+		},
+		{
+			md:   "```overview:output\n[1 2]\n```\n\n```go\ncode\n```\n\n````overview:output\n[3 4]\n````\n",
+			want: "lesson.md:9: duplicate block overview:output: the block is already specified at line 1",
+		},
+		{
+			md:   "```overview:code file=a.gx\ncode\n```\n\n```overview:code file=a.gx\ncode\n```\n",
+			want: "lesson.md:5: duplicate block overview:code file=a.gx: the block is already specified at line 1",
+		},
+		{
+			md:   "Text\n\n   ```overview:output\n   [1 2]\n   ```\n\n ```go\n code\n ```\n\n  ```overview:output\n  [3 4]\n  ```\n",
+			want: "lesson.md:11: duplicate block overview:output: the block is already specified at line 3",
+		},
+		{
+			md:   "```overview:output\n[1 2]\n```\n\n- item\n\n  ```go\n  code\n  ```\n\n  ```overview:output\n  [3 4]\n  ```\n",
+			want: "lesson.md:11: duplicate block overview:output: the block is already specified at line 1",
+		},
+		{
+			md:   "```overview:output\n[1 2]\n```\n\n    ```go\n    code\n\n```overview:output\n[3 4]\n```\n",
+			want: "lesson.md:8: duplicate block overview:output: the block is already specified at line 1",
+		},
+		{
+			md:   "Some code:\n\n    # comment\n    code\n\n# Title\n\n# Other\n",
+			want: `lesson.md:8: duplicate title "Other": the title is already specified at line 6`,
+		},
+		{
+			md:   "<div>\n# not a title\n</div>\n\n# Title\n\n# Other\n",
+			want: `lesson.md:7: duplicate title "Other": the title is already specified at line 5`,
+		},
+		{
+			md:   "# The `x` *type*\n\n# Other\n",
+			want: `lesson.md:3: duplicate title "Other": the title is already specified`,
+		},
+		{
+			md:   "```overview:code\ncode\n```\n\n```overview:code highlight=3\ncode\n```\n",
+			want: "lesson.md:5: overview:code: highlight: invalid line range 3: the code has 1 lines",
+		},
+		{
+			md:   "```overview:output\n[1 2]\n```\n\n````md\n```overview:output\n````\n\n```overview:output\n[3 4]\n```\n",
+			want: "lesson.md: duplicate block overview:output: the block is already specified",
+		},
+		{
+			md:   "Some text\n\n> ```overview:unknown\n> code\n> ```\n",
+			want: "lesson.md: unknown directive overview:unknown",
+		},
+		{
+			md:   "Some text\n\nand $\\unknown$ math\n",
+			want: `lesson.md:3: cannot convert math "\\unknown": unknown command \unknown`,
+		},
+		{
+			md:   "`\\unknown`\n\nand $\\unknown$ math\n",
+			want: `lesson.md:3: cannot convert math "\\unknown": unknown command \unknown`,
+		},
+		{
+			md:   "$x$ and `$\\unknown$`\n\n```\n$\\unknown$\n```\n\nand $\\unknown$ math\n",
+			want: `lesson.md: cannot convert math "\\unknown": unknown command \unknown`,
+		},
+		{
+			md:   "---\ntitle: A\n",
+			want: "lesson.md:1: front matter not closed by ---",
+		},
+	}
+	for i, test := range tests {
+		_, err := mdtext.ParseFile("lesson.md", []byte(test.md))
+		if err == nil {
+			t.Errorf("test %d: expected an error", i)
+			continue
+		}
+		if got := err.Error(); got != test.want {
+			t.Errorf("test %d: unexpected error:\ngot:  %s\nwant: %s", i, got, test.want)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	reg := mdtext.NewRegistry()
+	if err := reg.Register("note", func(mdt *mdtext.MDText, dir *mdtext.Directive) error {
+		mdt.Data["note"] = dir.Attrs["level"] + ": " + dir.Code
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register("code", nil); err == nil {
+		t.Errorf("expected an error when registering a directive twice")
+	}
+	const md = "Some text\n\n```overview:note level=info\nremember\n```\n"
+	if _, err := mdtext.Parse([]byte(md)); err == nil {
+		t.Errorf("expected an error for a directive not in the default registry")
+	}
+	mdText, err := mdtext.Parse([]byte(md), mdtext.WithRegistry(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := mdText.Data["note"], "info: remember\n"; got != want {
+		t.Errorf("got note %q but want %q", got, want)
+	}
+	if want := "<p>Some text</p>\n"; mdText.HTML != want {
+		t.Errorf("unexpected HTML:\ngot:\n%s\nwant:\n%s", mdText.HTML, want)
+	}
+}
+
+func TestReplaceCode(t *testing.T) {
+	const md = "# Title\n\n```overview:code\nsome code\n```\n\n````overview:output\n[1 2]\n````\n"
+	tests := []struct {
+		tag, code string
+		want      string
+		err       bool
+	}{
+		{
+			tag:  mdtext.OutputTag,
+			code: "[3 4]",
+			want: "# Title\n\n```overview:code\nsome code\n```\n\n````overview:output\n[3 4]\n````\n",
+		},
+		{
+			tag:  mdtext.CodeTag,
+			code: "other code\nover two lines\n",
+			want: "# Title\n\n```overview:code\nother code\nover two lines\n```\n\n````overview:output\n[1 2]\n````\n",
+		},
+		{tag: mdtext.SolutionTag, err: true},
+	}
+	for i, test := range tests {
+		got, err := mdtext.ReplaceCode([]byte(md), test.tag, test.code)
+		if test.err {
+			if err == nil {
+				t.Errorf("test %d: expected an error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: %v", i, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("test %d: unexpected markdown:\ngot:\n%s\nwant:\n%s", i, got, test.want)
+		}
+	}
+}
+
+func TestLessonLinks(t *testing.T) {
+	const md = "See [arrays](lesson:arrays) and [GX](https://github.com/gx-org/gx).\n"
+	mdText, err := mdtext.Parse([]byte(md))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"arrays"}, mdText.LessonLinks); diff != "" {
+		t.Errorf("unexpected lesson links (-want +got):\n%s", diff)
+	}
+	const wantHTML = `<p>See <a data-lesson="arrays" href="./index.html?lesson=arrays">arrays</a> and <a href="https://github.com/gx-org/gx" target="_blank">GX</a>.</p>
+`
+	if mdText.HTML != wantHTML {
+		t.Errorf("unexpected HTML:\ngot:\n%s\nwant:\n%s", mdText.HTML, wantHTML)
+	}
+}
+
+func TestMath(t *testing.T) {
+	tests := []struct {
+		md   string
+		want string
+		err  bool
+	}{
+		{
+			md:   "Inline $x^2$ math\n",
+			want: "<p>Inline <math><msup><mi>x</mi><mn>2</mn></msup></math> math</p>\n",
+		},
+		{
+			md:   "$$\n\\frac{a}{b}\n$$\n",
+			want: "<math display=\"block\"><mfrac><mi>a</mi><mi>b</mi></mfrac></math>\n",
+		},
+		{
+			md:  "Unknown $\\unknown$ command\n",
+			err: true,
+		},
+	}
+	for i, test := range tests {
+		mdText, err := mdtext.Parse([]byte(test.md))
+		if test.err {
+			if err == nil {
+				t.Errorf("test %d: expected an error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: %v", i, err)
+			continue
+		}
+		if mdText.HTML != test.want {
+			t.Errorf("test %d: unexpected HTML:\ngot:\n%s\nwant:\n%s", i, mdText.HTML, test.want)
+		}
+	}
+}
+
+func TestSnippets(t *testing.T) {
+	const md = "Some text\n\n```gx\npackage main\n\nfunc Main() float32 {\n\treturn 1 < 2\n}\n```\n\n" +
+		"```gx\npackage helpers\n```\n\n```gx noload\npackage main\n```\n\n```go\nfunc main() {}\n```\n"
+	mdText, err := mdtext.Parse([]byte(md))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"package main\n\nfunc Main() float32 {\n\treturn 1 < 2\n}\n"}, mdText.Snippets); diff != "" {
+		t.Errorf("unexpected snippets (-want +got):\n%s", diff)
+	}
+	const wantHTML = `<p>Some text</p>
+<div class="gx_snippet"><pre class="gx_code"><code><span class="gx_keyword">package</span> main
+
+<span class="gx_keyword">func</span> Main() <span class="gx_type">float32</span> {
+	<span class="gx_keyword">return</span> 1 &lt; 2
+}</code></pre><button class="gx_snippet_load" data-snippet="0">Load into editor</button></div>
+<div class="gx_snippet"><pre class="gx_code"><code><span class="gx_keyword">package</span> helpers</code></pre></div>
+<div class="gx_snippet"><pre class="gx_code"><code><span class="gx_keyword">package</span> main</code></pre></div>
+
+<pre><code class="language-go">func main() {}
+</code></pre>
+`
+	if mdText.HTML != wantHTML {
+		t.Errorf("unexpected HTML:\ngot:\n%s\nwant:\n%s", mdText.HTML, wantHTML)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{
+			src:  `<p class="note">Some <b onclick="alert(1)">bold</b> text</p>`,
+			want: `<p class="note">Some <b>bold</b> text</p>`,
+		},
+		{
+			src:  `before<script>alert("<p>")</script>after`,
+			want: `beforeafter`,
+		},
+		{
+			src:  `<a href="javascript:alert(1)">x</a><a href="https://gx-org.github.io" target="_blank">y</a>`,
+			want: `<a>x</a><a href="https://gx-org.github.io" target="_blank">y</a>`,
+		},
+		{
+			src:  `<font color="red">red</font><!-- comment --><img src="x.png" onerror="alert(1)">`,
+			want: `red<img src="x.png">`,
+		},
+		{
+			src:  `<img src="https://example.com/x.png" alt="remote"><img src="//example.com/x.png"><img src="data:image/png;base64,iVBORw0K"><img src="data:text/html,x">`,
+			want: `<img alt="remote"><img><img src="data:image/png;base64,iVBORw0K"><img>`,
+		},
+		{
+			src:  `<iframe src="https://example.com"><p>inside</p></iframe><div><iframe></iframe>kept</div>`,
+			want: `<div>kept</div>`,
+		},
+		{
+			src:  `<math display="block"><mi mathvariant="bold" style="color:red">x</mi></math>`,
+			want: `<math display="block"><mi mathvariant="bold">x</mi></math>`,
+		},
+	}
+	for i, test := range tests {
+		if got := mdtext.Sanitize(test.src); got != test.want {
+			t.Errorf("test %d: unexpected HTML:\ngot:  %s\nwant: %s", i, got, test.want)
+		}
+	}
+}
+
+func TestTrustedHTML(t *testing.T) {
+	const md = "Some text\n\n<div onclick=\"run()\">raw</div>\n"
+	mdText, err := mdtext.Parse([]byte(md))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<p>Some text</p>\n\n<div>raw</div>\n"; mdText.HTML != want {
+		t.Errorf("unexpected sanitized HTML:\ngot:\n%s\nwant:\n%s", mdText.HTML, want)
+	}
+	mdText, err = mdtext.Parse([]byte(md), mdtext.TrustedHTML())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<p>Some text</p>\n\n<div onclick=\"run()\">raw</div>\n"; mdText.HTML != want {
+		t.Errorf("unexpected trusted HTML:\ngot:\n%s\nwant:\n%s", mdText.HTML, want)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdtext
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// Error is an error at a line of a markdown file.
+type Error struct {
+	// File is the name of the markdown file or an empty string if the name is unknown.
+	File string
+	// Line of the error, starting from 1, or 0 if the line is unknown.
+	Line int
+	Err  error
+}
+
+// Errorf returns an error at a line of a markdown file.
+func Errorf(file string, line int, format string, a ...any) *Error {
+	return &Error{File: file, Line: line, Err: fmt.Errorf(format, a...)}
+}
+
+func (err *Error) Error() string {
+	switch {
+	case err.File != "" && err.Line > 0:
+		return fmt.Sprintf("%s:%d: %v", err.File, err.Line, err.Err)
+	case err.File != "":
+		return fmt.Sprintf("%s: %v", err.File, err.Err)
+	case err.Line > 0:
+		return fmt.Sprintf("line %d: %v", err.Line, err.Err)
+	}
+	return err.Err.Error()
+}
+
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// atLine returns " at line n" or an empty string if the line is unknown.
+func atLine(line int) string {
+	if line <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" at line %d", line)
+}
+
+// openingFence returns the info string of a line opening a block fenced with backticks.
+func openingFence(line string) (fence, info string, ok bool) {
+	opening := strings.TrimSpace(line)
+	info = strings.TrimLeft(opening, "`")
+	fence = opening[:len(opening)-len(info)]
+	return fence, strings.TrimSpace(info), len(fence) >= 3
+}
+
+// sourceLines returns the lines, starting from 1, of a markdown source.
+func sourceLines(src []byte) []string {
+	return strings.SplitAfter(string(src), "\n")
+}
+
+// matchLines assigns a line to nodes identified by a key from the lines at which the key
+// is found in the source. The lines of the nodes sharing a key are known only if the key
+// is found exactly once per node, in which case nodes and lines are matched in order.
+// Other nodes have no line: an error without a line is better than an error at the wrong line.
+func matchLines(lines map[ast.Node]int, nodes []ast.Node, key func(ast.Node) string, find func(key string) []int) {
+	byKey := make(map[string][]ast.Node)
+	var keys []string
+	for _, node := range nodes {
+		k := key(node)
+		if _, ok := byKey[k]; !ok {
+			keys = append(keys, k)
+		}
+		byKey[k] = append(byKey[k], node)
+	}
+	for _, k := range keys {
+		found := find(k)
+		if len(found) != len(byKey[k]) {
+			continue
+		}
+		for i, node := range byKey[k] {
+			lines[node] = found[i]
+		}
+	}
+}
+
+// nodeLines returns the lines of the fenced code blocks and of the level 1 headings of a document.
+// A fenced code block is located from the lines opening a fence with the same info string
+// and a heading from the lines with a # followed by its text.
+func nodeLines(doc ast.Node, src []byte) map[ast.Node]int {
+	var fenceNodes, titleNodes []ast.Node
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.GoToNext
+		}
+		switch nodeT := node.(type) {
+		case *ast.CodeBlock:
+			if nodeT.IsFenced {
+				fenceNodes = append(fenceNodes, node)
+			}
+		case *ast.Heading:
+			if nodeT.Level == 1 {
+				titleNodes = append(titleNodes, node)
+			}
+		}
+		return ast.GoToNext
+	})
+	fences := make(map[string][]int)
+	titles := make(map[string][]int)
+	for i, line := range sourceLines(src) {
+		if _, info, ok := openingFence(line); ok {
+			fences[info] = append(fences[info], i+1)
+		}
+		if title, ok := strings.CutPrefix(strings.TrimSpace(line), "# "); ok {
+			titles[strings.TrimSpace(title)] = append(titles[strings.TrimSpace(title)], i+1)
+		}
+	}
+	lines := make(map[ast.Node]int)
+	matchLines(lines, fenceNodes, func(node ast.Node) string {
+		return strings.TrimSpace(string(node.(*ast.CodeBlock).Info))
+	}, func(info string) []int { return fences[info] })
+	matchLines(lines, titleNodes, plainText, func(title string) []int { return titles[title] })
+	return lines
+}
+
+// substringLines returns the line at which each occurrence of s starts in a source.
+func substringLines(src []byte, s string) []int {
+	var lines []int
+	for pos := 0; s != ""; {
+		i := bytes.Index(src[pos:], []byte(s))
+		if i < 0 {
+			break
+		}
+		lines = append(lines, bytes.Count(src[:pos+i], []byte("\n"))+1)
+		pos += i + len(s)
+	}
+	return lines
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdtext
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// globalAttrs are the attributes allowed on all elements.
+	globalAttrs = []string{"id", "class", "title", "lang", "dir"}
+
+	// allowedElements are the elements kept by the sanitizer with their allowed attributes,
+	// in addition to the global attributes.
+	allowedElements = map[string][]string{
+		// Text.
+		"p": nil, "br": nil, "hr": nil, "div": nil, "span": nil, "blockquote": nil,
+		"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+		"em": nil, "strong": nil, "b": nil, "i": nil, "u": nil, "s": nil, "del": nil, "ins": nil,
+		"sub": nil, "sup": nil, "small": nil, "mark": nil, "kbd": nil, "abbr": nil,
+		"code": nil, "pre": nil,
+		"ul": nil, "ol": {"start"}, "li": nil, "dl": nil, "dt": nil, "dd": nil,
+		"a":   {"href", "target", "rel", LessonAttr},
+		"img": {"src", "alt", "width", "height"},
+		// Tables.
+		"table": nil, "thead": nil, "tbody": nil, "tfoot": nil, "tr": nil, "caption": nil,
+		"th": {"align", "colspan", "rowspan"}, "td": {"align", "colspan", "rowspan"},
+		// Buttons loading snippets into the editor.
+		"button": {SnippetAttr},
+		// MathML generated for math.
+		"math": {"display"}, "mrow": nil, "mi": {"mathvariant"}, "mn": {"mathvariant"},
+		"mo":    {"fence", "stretchy", "largeop", "movablelimits"},
+		"mtext": nil, "mspace": {"width"}, "mfrac": nil, "msqrt": nil, "mroot": nil,
+		"msub": nil, "msup": nil, "msubsup": nil, "munder": nil, "mover": {"accent"}, "munderover": nil,
+	}
+
+	// droppedElements are removed by the sanitizer with their content.
+	droppedElements = map[string]bool{
+		"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+		"template": true, "noscript": true, "textarea": true, "title": true, "svg": true,
+		"form": true, "select": true,
+	}
+
+	// urlAttrs are the attributes with a URL.
+	urlAttrs = map[string]bool{"href": true, "src": true}
+)
+
+// safeURL returns true if a URL is relative or uses a scheme which cannot run code.
+func safeURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// safeImageURL returns true if an image URL is relative or a data URL of an image.
+// Other images are blocked by the Content Security Policy of the server.
+func safeImageURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "":
+		return u.Host == ""
+	case "data":
+		return strings.HasPrefix(strings.ToLower(u.Opaque), "image/")
+	}
+	return false
+}
+
+func allowedAttr(elem, attr string) bool {
+	return slices.Contains(globalAttrs, attr) || slices.Contains(allowedElements[elem], attr)
+}
+
+func sanitizeAttrs(tok *html.Token) {
+	var attrs []html.Attribute
+	for _, attr := range tok.Attr {
+		if attr.Namespace != "" || !allowedAttr(tok.Data, attr.Key) {
+			continue
+		}
+		switch {
+		case tok.Data == "img" && attr.Key == "src":
+			if !safeImageURL(attr.Val) {
+				continue
+			}
+		case urlAttrs[attr.Key]:
+			if !safeURL(attr.Val) {
+				continue
+			}
+		}
+		attrs = append(attrs, attr)
+	}
+	tok.Attr = attrs
+}
+
+// Sanitize returns HTML in which only the elements and attributes of an allowlist are kept.
+// The content of elements not in the allowlist is kept unless the element can run code or
+// embed other documents (e.g. script or iframe). Comments are removed and URLs
+// with schemes other than http, https, and mailto are dropped.
+// Images are only kept with relative or data URLs.
+func Sanitize(src string) string {
+	var out strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(src))
+	// dropped is the name and the depth of the element being dropped with its content.
+	dropped, depth := "", 0
+	for {
+		if tokenizer.Next() == html.ErrorToken {
+			// The tokenizer reads from a string: the only error is io.EOF.
+			return out.String()
+		}
+		tok := tokenizer.Token()
+		if dropped != "" {
+			switch {
+			case tok.Type == html.StartTagToken && tok.Data == dropped:
+				depth++
+			case tok.Type == html.EndTagToken && tok.Data == dropped:
+				depth--
+			}
+			if depth == 0 {
+				dropped = ""
+			}
+			continue
+		}
+		switch tok.Type {
+		case html.TextToken:
+			out.WriteString(tok.String())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedElements[tok.Data] {
+				if tok.Type == html.StartTagToken {
+					dropped, depth = tok.Data, 1
+				}
+				continue
+			}
+			if _, ok := allowedElements[tok.Data]; !ok {
+				continue
+			}
+			sanitizeAttrs(&tok)
+			out.WriteString(tok.String())
+		case html.EndTagToken:
+			if _, ok := allowedElements[tok.Data]; ok {
+				out.WriteString(tok.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdtext
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gx-org/gx-org/internal/highlight"
+)
+
+// SnippetLanguage is the language of the code blocks displayed
+// in the text with a button to load them into the editor.
+const SnippetLanguage = "gx"
+
+// SnippetNoLoad is the attribute of the snippets displayed without a button (e.g. ```gx noload).
+// Only snippets of package main can be loaded into the editor.
+const SnippetNoLoad = "noload"
+
+// SnippetAttr is the HTML attribute storing the index of a snippet
+// in MDText.Snippets on the button loading the snippet into the editor.
+const SnippetAttr = "data-snippet"
+
+var packageMainRegexp = regexp.MustCompile(`(?m)^package\s+main\s*$`)
+
+// processSnippets stores the index of the snippets in sources.
+// Snippets displayed without a button have the index -1.
+func processSnippets(snippets map[ast.Node]int, sources *[]string) func(node *ast.CodeBlock) ast.WalkStatus {
+	return func(node *ast.CodeBlock) ast.WalkStatus {
+		info := strings.Fields(string(node.Info))
+		if len(info) == 0 || info[0] != SnippetLanguage {
+			return ast.GoToNext
+		}
+		if slices.Contains(info[1:], SnippetNoLoad) || !packageMainRegexp.Match(node.Literal) {
+			snippets[node] = -1
+			return ast.GoToNext
+		}
+		snippets[node] = len(*sources)
+		*sources = append(*sources, string(node.Literal))
+		return ast.GoToNext
+	}
+}
+
+// renderSnippets returns a renderer hook writing highlighted snippets with their button.
+func renderSnippets(snippets map[ast.Node]int) html.RenderNodeFunc {
+	return func(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+		i, ok := snippets[node]
+		if !ok {
+			return ast.GoToNext, false
+		}
+		if !entering {
+			return ast.GoToNext, true
+		}
+		code := highlight.Code(string(node.AsLeaf().Literal))
+		if i < 0 {
+			fmt.Fprintf(w, `<div class="gx_snippet">%s</div>`+"\n", code)
+			return ast.GoToNext, true
+		}
+		fmt.Fprintf(w, `<div class="gx_snippet">%s<button class="gx_snippet_load" %s="%d">Load into editor</button></div>`+"\n",
+			code, SnippetAttr, i)
+		return ast.GoToNext, true
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package project locates the files of the overview on disk.
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FindParent returns the first parent of the current working directory
+// (including the directory itself) containing folder.
+func FindParent(folder string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for cwd != "/" {
+		modPath := filepath.Join(cwd, folder)
+		if _, err := os.Stat(modPath); err == nil {
+			return cwd, nil
+		}
+		cwd = filepath.Dir(cwd)
+	}
+	return "", fmt.Errorf("parent %s not found", folder)
+}
+
+// Root returns the root of the project, that is the folder
+// containing index.html and the res folder.
+func Root() (string, error) {
+	return FindParent(".git")
+}
+
+// ModuleRoot returns the root of the Go module.
+func ModuleRoot() (string, error) {
+	return FindParent("go.mod")
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package share stores GX code shared by users.
+package share
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+type (
+	// Snippet is some GX code shared by a user
+	// together with the lesson in which it has been written.
+	Snippet struct {
+		Source  string `json:"source"`
+		Chapter int    `json:"chapter"`
+		Lesson  int    `json:"lesson"`
+		// Slug of the lesson. Empty for snippets shared before lessons had slugs.
+		Slug string `json:"slug,omitempty"`
+	}
+
+	// Store stores snippets given their ID.
+	Store interface {
+		// Put stores a snippet and returns its ID.
+		Put(*Snippet) (string, error)
+		// Get returns the snippet given its ID.
+		// ErrNotFound is returned if the snippet does not exist.
+		Get(id string) (*Snippet, error)
+	}
+)
+
+// ErrNotFound is returned when a snippet does not exist.
+var ErrNotFound = errors.New("snippet not found")
+
+const idBytes = 9
+
+var idRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{12}$`)
+
+// ID returns the ID of a snippet.
+// IDs are derived from the content of snippets,
+// so sharing the same code twice returns the same ID.
+func ID(snip *Snippet) (string, error) {
+	data, err := json.Marshal(snip)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.URLEncoding.EncodeToString(sum[:idBytes]), nil
+}
+
+// ValidID returns true if id has the format of a snippet ID.
+func ValidID(id string) bool {
+	return idRegexp.MatchString(id)
+}
+
+// FileStore stores snippets as JSON files in a folder.
+type FileStore struct {
+	dir string
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore returns a store writing snippets in dir.
+// The folder is created if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("cannot create snippet store: %v", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// Put writes a snippet into the store folder.
+func (s *FileStore) Put(snip *Snippet) (string, error) {
+	id, err := ID(snip)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(snip)
+	if err != nil {
+		return "", err
+	}
+	// Write to a temporary file first so that a snippet is never read partially written.
+	tmp, err := os.CreateTemp(s.dir, id+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), s.path(id)); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return id, nil
+}
+
+// Get reads a snippet from the store folder.
+func (s *FileStore) Get(id string) (*Snippet, error) {
+	if !ValidID(id) {
+		return nil, ErrNotFound
+	}
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	snip := &Snippet{}
+	if err := json.Unmarshal(data, snip); err != nil {
+		return nil, fmt.Errorf("snippet %s is corrupted: %v", id, err)
+	}
+	return snip, nil
+}
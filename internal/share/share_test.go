@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package share_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gx-org/gx-org/internal/share"
+)
+
+func TestFileStore(t *testing.T) {
+	store, err := share.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	snips := []*share.Snippet{
+		{Source: "package main\n", Chapter: 1, Lesson: 1},
+		{Source: "package main\n", Chapter: 1, Lesson: 2},
+		{Source: "package main\n\nfunc Main() int32 {\n\treturn 1\n}\n", Chapter: 2, Lesson: 1},
+	}
+	ids := make(map[string]bool)
+	for i, snip := range snips {
+		id, err := store.Put(snip)
+		if err != nil {
+			t.Fatalf("snippet %d: %v", i, err)
+		}
+		if !share.ValidID(id) {
+			t.Errorf("snippet %d: invalid ID %q", i, id)
+		}
+		if ids[id] {
+			t.Errorf("snippet %d: ID %q already used", i, id)
+		}
+		ids[id] = true
+		again, err := store.Put(snip)
+		if err != nil {
+			t.Fatalf("snippet %d: %v", i, err)
+		}
+		if again != id {
+			t.Errorf("snippet %d: storing the same snippet twice returned %q and %q", i, id, again)
+		}
+		got, err := store.Get(id)
+		if err != nil {
+			t.Fatalf("snippet %d: %v", i, err)
+		}
+		if diff := cmp.Diff(snip, got); diff != "" {
+			t.Errorf("snippet %d: unexpected snippet (-want +got):\n%s", i, diff)
+		}
+	}
+	for _, id := range []string{"", "AAAAAAAAAAAA", "../../etc/pw", "not-an-id"} {
+		if _, err := store.Get(id); !errors.Is(err, share.ErrNotFound) {
+			t.Errorf("Get(%q): got error %v but want %v", id, err, share.ErrNotFound)
+		}
+	}
+}
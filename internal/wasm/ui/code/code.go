@@ -21,17 +21,11 @@ import (
 	"runtime/debug"
 	"strings"
 
+	"github.com/gx-org/gx-org/internal/golden"
+	"github.com/gx-org/gx-org/internal/gxrun"
 	"github.com/gx-org/gx-org/internal/lessons"
+	"github.com/gx-org/gx-org/internal/linediff"
 	"github.com/gx-org/gx-org/internal/wasm/ui"
-	"github.com/gx-org/gx/api"
-	"github.com/gx-org/gx/api/tracer"
-	"github.com/gx-org/gx/api/values"
-	"github.com/gx-org/gx/build/builder"
-	"github.com/gx-org/gx/build/importers"
-	"github.com/gx-org/gx/build/ir"
-	"github.com/gx-org/gx/golang/backend"
-	"github.com/gx-org/gx/golang/backend/kernels"
-	"github.com/gx-org/gx/stdlib"
 	"honnef.co/go/js/dom/v2"
 )
 
@@ -40,33 +34,48 @@ type Code struct {
 	src *Source
 	out *Output
 
-	bld    *builder.Builder
-	dev    *api.Device
-	devErr error
+	run    *gxrun.Runner
+	lesson *lessons.Lesson
 }
 
 func New(gui *ui.UI, parent dom.HTMLElement) *Code {
-	bld := builder.New(importers.NewCacheLoader(
-		stdlib.Importer(nil),
-	))
 	cd := &Code{
 		gui: gui,
-		bld: bld,
+		run: gxrun.New(),
 	}
 	container := gui.CreateDIV(parent, ui.Class("code_container"))
 	cd.src = newSource(cd, container)
 	cd.out = newOutput(cd, container)
-
-	cd.dev, cd.devErr = backend.New(bld).Device(0)
 	return cd
 }
 
 func (cd *Code) SetContent(les *lessons.Lesson) {
-	cd.src.set(les.Code, nil)
+	if len(les.PackageDirs) > 0 || (cd.lesson != nil && len(cd.lesson.PackageDirs) > 0) {
+		// Packages are cached by the builder: use a new runner for lessons with their own packages.
+		cd.run = gxrun.New(les.Packages()...)
+	}
+	cd.lesson = les
+	cd.src.setFiles(les.Sources, les.Highlights)
+	cd.src.setSolutionVisible(len(les.Solution) > 0)
+}
+
+// SetSource replaces the sources in the editor given a string returned by lessons.FormatFiles.
+// The previous sources can be recovered with undo.
+func (cd *Code) SetSource(src string) {
+	cd.src.loadFiles(lessons.ParseFiles(src))
 }
 
-func (cd *Code) compileAndWrite(src string) error {
-	_, err := cd.compileCode(src)
+// LoadSnippet replaces the source of the file displayed in the editor.
+// The previous source can be recovered with undo.
+func (cd *Code) LoadSnippet(src string) {
+	cd.src.set(src, nil)
+	ui.Go(func() {
+		cd.callAndWrite(cd.compileAndWrite)
+	})
+}
+
+func (cd *Code) compileAndWrite() error {
+	_, err := cd.compileCode()
 	if err != nil {
 		return err
 	}
@@ -74,113 +83,123 @@ func (cd *Code) compileAndWrite(src string) error {
 	return nil
 }
 
-func (cd *Code) compileCode(src string) (*ir.Package, error) {
-	if cd.devErr != nil {
-		return nil, fmt.Errorf("Cannot initialise backend: %s", cd.devErr.Error())
+// showSolution displays the differences between the file in the editor and its solution.
+func (cd *Code) showSolution() error {
+	if cd.lesson == nil || len(cd.lesson.Solution) == 0 {
+		return fmt.Errorf("this lesson has no solution")
+	}
+	name := cd.src.activeFile()
+	solution := lessons.FindFile(cd.lesson.Solution, name)
+	if solution == nil {
+		return fmt.Errorf("%s has no solution: the solution is in %s", name, cd.lesson.Solution[0].Name)
 	}
-	pkg := cd.bld.NewIncrementalPackage("main")
-	if err := pkg.Build(src); err != nil {
-		return nil, err
+	cd.out.setDiff(linediff.Diff(cd.src.source.Current().src, solution.Source), func(dom.Event) {
+		cd.src.set(solution.Source, nil)
+		cd.out.set("")
+	})
+	return nil
+}
+
+// compileCode compiles the files of the editor with the hidden tests of the lesson.
+func (cd *Code) compileCode() (*gxrun.Package, error) {
+	var tests []lessons.SourceFile
+	if cd.lesson != nil {
+		tests = cd.lesson.TestFiles()
+	}
+	return cd.run.CompileWithTests(gxFiles(cd.src.files()), gxFiles(tests))
+}
+
+func gxFiles(files []lessons.SourceFile) []gxrun.File {
+	gxFiles := make([]gxrun.File, len(files))
+	for i, file := range files {
+		gxFiles[i] = gxrun.File(file)
 	}
-	return pkg.IR(), nil
+	return gxFiles
 }
 
-func (cd *Code) callAndWrite(f func(src string) error, src string) {
+func (cd *Code) callAndWrite(f func() error) {
 	defer func() {
 		if r := recover(); r != nil {
+			src := lessons.FormatFiles(cd.src.files())
 			cd.out.set(fmt.Sprintf("GX PANIC: please report everything below so that it can be fixed:\n%s\n%s", src, debug.Stack()))
 		}
 	}()
-	if err := f(src); err != nil {
+	if err := f(); err != nil {
 		cd.out.set(fmt.Sprintf("ERROR: %s", err.Error()))
 		return
 	}
 }
 
-func flatten(out []values.Value) []values.Value {
-	flat := []values.Value{}
-	for _, v := range out {
-		slice, ok := v.(*values.Slice)
-		if !ok {
-			flat = append(flat, v)
-			continue
-		}
-		vals := make([]values.Value, slice.Size())
-		for i := 0; i < slice.Size(); i++ {
-			vals[i] = slice.Element(i)
-		}
-		flat = append(flat, flatten(vals)...)
+func indent(s string) string {
+	var lines []string
+	for line := range strings.Lines(s) {
+		lines = append(lines, "  "+line)
+	}
+	if lines[len(lines)-1] != "\n" {
+		lines = append(lines, "\n")
 	}
-	return flat
+	return strings.Join(lines, "")
 }
 
-func buildString(bld *strings.Builder, out []values.Value) error {
-	out, err := values.ToHost(kernels.Allocator(), flatten(out))
+func (cd *Code) runCode() error {
+	pkg, err := cd.compileCode()
 	if err != nil {
 		return err
 	}
-	if len(out) == 0 {
-		return nil
-	}
-	if len(out) == 1 {
-		bld.WriteString(fmt.Sprint(out[0]))
-		return nil
+	bld := strings.Builder{}
+	var args []gxrun.Args
+	if cd.lesson != nil {
+		for _, fa := range cd.lesson.Args {
+			args = append(args, gxrun.Args(fa))
+		}
 	}
-	for i, s := range out {
-		bld.WriteString(fmt.Sprintf("%d: %v\n", i, s))
+	results := cd.run.Run(pkg, args...)
+	for _, res := range results {
+		bld.WriteString(res.Name + ":\n")
+		if res.Err != nil {
+			bld.WriteString(indent(res.Err.Error()))
+			continue
+		}
+		bld.WriteString(indent(res.Output))
 	}
+	bld.WriteString(cd.checkOutput(results))
+	bld.WriteString(cd.runTests(pkg))
+	cd.out.set(bld.String())
 	return nil
 }
 
-func (cd *Code) runFunc(fun ir.Func, args []values.Value) ([]values.Value, string, bool) {
-	numArgs := fun.FuncType().Params.Len()
-	if len(args) < numArgs {
-		return nil, fmt.Sprintf("not enough arguments to pass to %s: got %d but want %d", fun.Name(), len(args), numArgs), false
+// checkOutput compares the output of the last function with the expected output of the lesson.
+// It returns an empty string if the lesson has no expected output.
+func (cd *Code) checkOutput(results []*gxrun.Result) string {
+	if cd.lesson == nil || cd.lesson.Output == "" || len(results) == 0 {
+		return ""
 	}
-	args = args[:numArgs]
-	runner, err := tracer.Trace(cd.dev, fun.(*ir.FuncDecl), nil, args, nil)
-	if err != nil {
-		return nil, err.Error(), false
+	last := results[len(results)-1]
+	err := last.Err
+	if err == nil {
+		err = golden.Match(last.Output, cd.lesson.Output)
 	}
-	vals, err := runner.Run(nil, args, nil)
-	if err != nil {
-		return nil, err.Error(), false
+	if err == nil {
+		return "\n✓ Output matches the expected result.\n"
 	}
-	bld := strings.Builder{}
-	if err := buildString(&bld, vals); err != nil {
-		return nil, err.Error(), false
-	}
-	return vals, bld.String(), true
+	return fmt.Sprintf("\n✗ Output does not match the expected result (%v). Expected:\n%s", err, indent(cd.lesson.Output))
 }
 
-func indent(s string) string {
-	var lines []string
-	for line := range strings.Lines(s) {
-		lines = append(lines, "  "+line)
-	}
-	if lines[len(lines)-1] != "\n" {
-		lines = append(lines, "\n")
-	}
-	return strings.Join(lines, "")
-}
-
-func (cd *Code) runCode(src string) error {
-	irPkg, err := cd.compileCode(src)
-	if err != nil {
-		return err
+// runTests runs the hidden tests of the lesson and returns a line per test
+// telling if the test passed. It returns an empty string if the lesson has no test.
+func (cd *Code) runTests(pkg *gxrun.Package) string {
+	results := cd.run.RunTests(pkg)
+	if len(results) == 0 {
+		return ""
 	}
 	bld := strings.Builder{}
-	var vals []values.Value
-	for fun := range irPkg.ExportedFuncs() {
-		bld.WriteString(fun.Name() + ":\n")
-		var s string
-		var ok bool
-		vals, s, ok = cd.runFunc(fun, vals)
-		bld.WriteString(indent(s))
-		if !ok {
-			break
+	bld.WriteString("\nTests:\n")
+	for _, res := range results {
+		if res.Err != nil {
+			bld.WriteString(fmt.Sprintf("  ✗ %s\n%s", res.Name, indent(indent(res.Err.Error()))))
+			continue
 		}
+		bld.WriteString(fmt.Sprintf("  ✓ %s\n", res.Name))
 	}
-	cd.out.set(bld.String())
-	return nil
+	return bld.String()
 }
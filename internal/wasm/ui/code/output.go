@@ -16,7 +16,10 @@ package code
 
 import (
 	"fmt"
+	"html"
+	"strings"
 
+	"github.com/gx-org/gx-org/internal/linediff"
 	"github.com/gx-org/gx-org/internal/wasm/ui"
 	"honnef.co/go/js/dom/v2"
 )
@@ -47,3 +50,22 @@ func (out *Output) set(src string) {
 	}
 	out.div.SetInnerHTML(fmt.Sprintf("<pre>%s%s<pre>", src, crash))
 }
+
+var diffPrefix = map[linediff.Op]struct {
+	class, prefix string
+}{
+	linediff.Equal:  {class: "diff_equal", prefix: "  "},
+	linediff.Delete: {class: "diff_delete", prefix: "- "},
+	linediff.Insert: {class: "diff_insert", prefix: "+ "},
+}
+
+// setDiff displays a diff with a button calling onLoad.
+func (out *Output) setDiff(lines []linediff.Line, onLoad ui.EventFunc) {
+	bld := strings.Builder{}
+	for _, line := range lines {
+		prefix := diffPrefix[line.Op]
+		bld.WriteString(fmt.Sprintf(`<span class="%s">%s%s</span>`+"\n", prefix.class, prefix.prefix, html.EscapeString(line.Text)))
+	}
+	out.div.SetInnerHTML(fmt.Sprintf("<pre>%s</pre>", bld.String()))
+	out.code.gui.CreateButton(out.div, "Load solution into editor", onLoad)
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wasm
+
+package code
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"syscall/js"
+
+	"github.com/gx-org/gx-org/internal/lessons"
+	"github.com/gx-org/gx-org/internal/share"
+	"github.com/gx-org/gx-org/internal/wasm/ui"
+)
+
+const shareAPI = "api/share"
+
+// resolve a URL relative to the current page.
+func resolve(gui *ui.UI, ref *url.URL) (string, error) {
+	loc, err := gui.URL()
+	if err != nil {
+		return "", err
+	}
+	return loc.ResolveReference(ref).String(), nil
+}
+
+// decodeResponse decodes the JSON body of a response from the server into v.
+func decodeResponse(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		apiErr := struct {
+			Error string `json:"error"`
+		}{}
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Error == "" {
+			return fmt.Errorf("server returned %s", resp.Status)
+		}
+		return fmt.Errorf("%s", apiErr.Error)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func copyToClipboard(s string) {
+	clipboard := js.Global().Get("navigator").Get("clipboard")
+	if clipboard.IsUndefined() {
+		return
+	}
+	clipboard.Call("writeText", s)
+}
+
+func (cd *Code) share() error {
+	if cd.lesson == nil {
+		return fmt.Errorf("no lesson to share")
+	}
+	body, err := json.Marshal(&share.Snippet{
+		Source:  lessons.FormatFiles(cd.src.files()),
+		Chapter: cd.lesson.Chapter.ID,
+		Lesson:  cd.lesson.ID,
+		Slug:    cd.lesson.Slug,
+	})
+	if err != nil {
+		return err
+	}
+	apiURL, err := resolve(cd.gui, &url.URL{Path: shareAPI})
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(apiURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot share code: %v", err)
+	}
+	shared := struct {
+		ID string `json:"id"`
+	}{}
+	if err := decodeResponse(resp, &shared); err != nil {
+		return fmt.Errorf("cannot share code: %v", err)
+	}
+	link, err := resolve(cd.gui, &url.URL{
+		Path:     "index.html",
+		RawQuery: url.Values{"share": []string{shared.ID}}.Encode(),
+	})
+	if err != nil {
+		return err
+	}
+	copyToClipboard(link)
+	cd.out.set("Link to share your code (copied to the clipboard):\n" + link)
+	return nil
+}
+
+// FetchSnippet fetches a shared snippet from the server given its ID.
+func FetchSnippet(gui *ui.UI, id string) (*share.Snippet, error) {
+	apiURL, err := resolve(gui, &url.URL{Path: shareAPI + "/" + url.PathEscape(id)})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.Get(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch shared code %s: %v", id, err)
+	}
+	snip := &share.Snippet{}
+	if err := decodeResponse(resp, snip); err != nil {
+		return nil, fmt.Errorf("cannot fetch shared code %s: %v", id, err)
+	}
+	return snip, nil
+}
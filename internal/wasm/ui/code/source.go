@@ -16,11 +16,14 @@ package code
 
 import (
 	"fmt"
-	"html"
+	"slices"
 	"strings"
 
+	"github.com/gx-org/gx-org/internal/highlight"
+	"github.com/gx-org/gx-org/internal/history"
+	"github.com/gx-org/gx-org/internal/lessons"
+	"github.com/gx-org/gx-org/internal/mdtext"
 	"github.com/gx-org/gx-org/internal/wasm/ui"
-	"github.com/gx-org/gx-org/internal/wasm/ui/history"
 	"honnef.co/go/js/dom/v2"
 )
 
@@ -44,11 +47,21 @@ func stateEq(a, b state) bool {
 type Source struct {
 	code      *Code
 	container *dom.HTMLDivElement
+	tabs      *dom.HTMLDivElement
 	input     *dom.HTMLDivElement
 	control   *dom.HTMLDivElement
+	solution  *dom.HTMLButtonElement
 
-	keys   *ui.Keys
+	keys *ui.Keys
+	// source is the history of the file displayed in the editor.
 	source *history.History[state]
+
+	// names and histories of all the files of the lesson.
+	names     []string
+	histories []*history.History[state]
+	active    int
+	// highlights are the lines to highlight given the name of a file.
+	highlights map[string][]mdtext.LineRange
 }
 
 func newSource(code *Code, parent dom.Element) *Source {
@@ -57,6 +70,10 @@ func newSource(code *Code, parent dom.Element) *Source {
 		container: code.gui.CreateDIV(parent, ui.Class("code_source_container")),
 		source:    history.New(stateEq),
 	}
+	s.tabs = code.gui.CreateDIV(parent,
+		ui.Class("code_source_tabs_container"),
+		ui.SetVisible(false),
+	)
 	s.input = code.gui.CreateDIV(parent,
 		ui.Class("code_source_textinput_container"),
 		ui.Property("contenteditable", "true"),
@@ -68,9 +85,80 @@ func newSource(code *Code, parent dom.Element) *Source {
 		ui.Class("code_source_controls_container"),
 	)
 	code.gui.CreateButton(s.control, "Run", s.onRun)
+	code.gui.CreateButton(s.control, "Share", s.onShare)
+	s.solution = code.gui.CreateButton(s.control, "Show solution", s.onShowSolution, ui.SetVisible(false))
 	return s
 }
 
+// setFiles replaces all the files in the editor and displays the first one.
+// Each file has its own undo history.
+func (s *Source) setFiles(files []lessons.SourceFile, highlights map[string][]mdtext.LineRange) {
+	s.highlights = highlights
+	s.names = make([]string, len(files))
+	s.histories = make([]*history.History[state], len(files))
+	for i, file := range files {
+		s.names[i] = file.Name
+		s.histories[i] = history.New(stateEq)
+		s.histories[i].Append(state{src: file.Source})
+	}
+	s.selectFile(0)
+}
+
+// loadFiles replaces the sources of the files with the same names.
+// A single file replaces the source of the file displayed in the editor
+// if the lesson has a single file.
+// The previous sources can be recovered with undo.
+func (s *Source) loadFiles(files []lessons.SourceFile) {
+	if len(files) == 1 && len(s.names) == 1 {
+		files[0].Name = s.names[0]
+	}
+	for _, file := range files {
+		i := slices.Index(s.names, file.Name)
+		if i < 0 {
+			continue
+		}
+		s.histories[i].Append(state{src: file.Source})
+	}
+	s.selectFile(s.active)
+}
+
+// selectFile displays a file in the editor.
+func (s *Source) selectFile(i int) {
+	s.active = i
+	s.source = s.histories[i]
+	s.render(s.source.Current())
+	ui.ClearChildren(s.tabs)
+	ui.SetVisible(len(s.names) > 1).Apply(s.tabs)
+	for i, name := range s.names {
+		opts := []ui.ElementOption{ui.Class("code_source_tab")}
+		if i == s.active {
+			opts = append(opts, ui.Class("code_source_tab_active"))
+		}
+		s.code.gui.CreateButton(s.tabs, name, func(dom.Event) {
+			s.selectFile(i)
+		}, opts...)
+	}
+}
+
+// activeFile returns the name of the file displayed in the editor.
+func (s *Source) activeFile() string {
+	return s.names[s.active]
+}
+
+// files returns the current source of all the files.
+func (s *Source) files() []lessons.SourceFile {
+	files := make([]lessons.SourceFile, len(s.names))
+	for i, name := range s.names {
+		files[i] = lessons.SourceFile{Name: name, Source: s.histories[i].Current().src}
+	}
+	return files
+}
+
+// setSolutionVisible shows or hides the button to display the solution.
+func (s *Source) setSolutionVisible(visible bool) {
+	ui.SetVisible(visible).Apply(s.solution)
+}
+
 func insertSource(src string, sel *ui.Selection, toInsert string) (string, *ui.Selection, bool) {
 	cursorLine := sel.Line()
 	var targetLines []string
@@ -152,26 +240,6 @@ func (s *Source) extractSource() string {
 	return src
 }
 
-var keywordToColor = []struct {
-	color string
-	words []string
-}{
-	{
-		color: "var(--language-keyword)",
-		words: []string{
-			"var", "const", "return", "struct", "func", "package", "import", "type", "interface",
-		},
-	},
-	{
-		color: "var(--type-keyword)",
-		words: []string{
-			"bool", "string",
-			"int32", "int64",
-			"bfloat64", "float32", "float64",
-		},
-	},
-}
-
 const tabSize = 4
 
 var tabSpaces = strings.Repeat(" ", tabSize)
@@ -179,29 +247,30 @@ var tabSpaces = strings.Repeat(" ", tabSize)
 func format(s string) string {
 	s = strings.ReplaceAll(s, "\t", tabSpaces)
 	s = strings.ReplaceAll(s, " ", "\u00a0")
-	s = html.EscapeString(s)
-	for _, color := range keywordToColor {
-		fontTag := fmt.Sprintf(`<span style="color:%s;">%%s</span>`, color.color)
-		for _, word := range color.words {
-			s = strings.ReplaceAll(s, word, fmt.Sprintf(fontTag, word))
-		}
-	}
-	return s
+	return highlight.HTML(s)
 }
 
 func (s *Source) set(src string, sel *ui.Selection) {
 	s.source.Append(state{src: src, sel: sel})
+	s.render(state{src: src, sel: sel})
+}
+
+func (s *Source) render(st state) {
+	src, sel := st.src, st.sel
 	parent := s.input
 	ui.ClearChildren(parent)
-	for _, line := range strings.Split(src, "\n") {
+	highlights := s.highlights[s.names[s.active]]
+	for i, line := range strings.Split(src, "\n") {
 		if line == "" {
 			line = "<br>"
 		} else {
 			line = format(line)
 		}
-		s.code.gui.CreateDIV(parent,
-			ui.InnerHTML(line),
-		)
+		opts := []ui.ElementOption{ui.InnerHTML(line)}
+		if slices.ContainsFunc(highlights, func(r mdtext.LineRange) bool { return r.Contains(i + 1) }) {
+			opts = append(opts, ui.Class("code_source_line_highlight"))
+		}
+		s.code.gui.CreateDIV(parent, opts...)
 	}
 	if sel != nil {
 		sel.SetAsCurrent()
@@ -209,7 +278,15 @@ func (s *Source) set(src string, sel *ui.Selection) {
 }
 
 func (s *Source) onRun(dom.Event) {
-	s.code.callAndWrite(s.code.runCode, s.source.Current().src)
+	s.code.callAndWrite(s.code.runCode)
+}
+
+func (s *Source) onShare(dom.Event) {
+	s.code.callAndWrite(s.code.share)
+}
+
+func (s *Source) onShowSolution(dom.Event) {
+	s.code.callAndWrite(s.code.showSolution)
 }
 
 func (s *Source) updateSource(process func(src string, sel *ui.Selection) (string, *ui.Selection, bool)) {
@@ -221,7 +298,7 @@ func (s *Source) updateSource(process func(src string, sel *ui.Selection) (strin
 	}
 	s.set(currentSrc, sel)
 	ui.Go(func() {
-		s.code.callAndWrite(s.code.compileAndWrite, currentSrc)
+		s.code.callAndWrite(s.code.compileAndWrite)
 	})
 
 }
@@ -18,8 +18,11 @@ package text
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/gx-org/gx-org/internal/lessons"
+	"github.com/gx-org/gx-org/internal/mdtext"
 	"github.com/gx-org/gx-org/internal/wasm/ui"
 	"honnef.co/go/js/dom/v2"
 )
@@ -35,6 +38,8 @@ type (
 
 	Page interface {
 		DisplayLesson(*lessons.Lesson)
+		// LoadSnippet replaces the source of the file displayed in the editor.
+		LoadSnippet(src string)
 	}
 )
 
@@ -49,6 +54,30 @@ func New(gui *ui.UI, parent dom.HTMLElement, page Page) *Text {
 	return text
 }
 
+// metaSummary returns a one-line summary of the metadata of a lesson.
+func metaSummary(meta *mdtext.FrontMatter) string {
+	var parts []string
+	if meta.Title != "" {
+		parts = append(parts, meta.Title)
+	}
+	if meta.Difficulty != "" {
+		parts = append(parts, meta.Difficulty)
+	}
+	if meta.Minutes > 0 {
+		parts = append(parts, fmt.Sprintf("~%d min", meta.Minutes))
+	}
+	if len(meta.Tags) > 0 {
+		parts = append(parts, "tags: "+strings.Join(meta.Tags, ", "))
+	}
+	if len(meta.Prerequisites) > 0 {
+		parts = append(parts, "requires: "+strings.Join(meta.Prerequisites, ", "))
+	}
+	if meta.MinGXVersion != "" {
+		parts = append(parts, "GX "+meta.MinGXVersion+" or later")
+	}
+	return strings.Join(parts, " · ")
+}
+
 func (tt *Text) SetContent(les *lessons.Lesson) {
 	ui.ClearChildren(tt.nav)
 	tt.gui.CreateButton(tt.nav, "←",
@@ -58,7 +87,11 @@ func (tt *Text) SetContent(les *lessons.Lesson) {
 		ui.SetVisible(les.Prev != nil),
 		ui.Class("navigation_button"),
 	)
-	tt.gui.CreateParagraph(tt.nav, fmt.Sprintf("Chapter %d, lesson %d/%d", les.Chapter.ID, les.ID, les.Chapter.NumLessons()))
+	info := tt.gui.CreateDIV(tt.nav, ui.Class("lesson_info"))
+	tt.gui.CreateParagraph(info, fmt.Sprintf("Chapter %d, lesson %d/%d", les.Chapter.ID, les.ID, les.Chapter.NumLessons()))
+	if meta := metaSummary(&les.Meta); meta != "" {
+		tt.gui.CreateParagraph(info, meta, ui.Class("lesson_meta"))
+	}
 	tt.gui.CreateButton(tt.nav, "→",
 		func(dom.Event) {
 			tt.page.DisplayLesson(les.Next)
@@ -67,4 +100,33 @@ func (tt *Text) SetContent(les *lessons.Lesson) {
 		ui.Class("navigation_button"),
 	)
 	tt.content.SetInnerHTML(les.HTML)
+	tt.setLessonLinks(les)
+	tt.setSnippetButtons(les)
+}
+
+// setLessonLinks displays the lessons targeted by links in the text without reloading the page.
+func (tt *Text) setLessonLinks(les *lessons.Lesson) {
+	for _, link := range tt.content.QuerySelectorAll("a[" + mdtext.LessonAttr + "]") {
+		target := les.Links[link.GetAttribute(mdtext.LessonAttr)]
+		if target == nil {
+			continue
+		}
+		ui.Listener("click", func(ev dom.Event) {
+			ev.PreventDefault()
+			tt.page.DisplayLesson(target)
+		}).Apply(link)
+	}
+}
+
+// setSnippetButtons loads the snippets of a lesson into the editor when their button is clicked.
+func (tt *Text) setSnippetButtons(les *lessons.Lesson) {
+	for _, button := range tt.content.QuerySelectorAll("button[" + mdtext.SnippetAttr + "]") {
+		i, err := strconv.Atoi(button.GetAttribute(mdtext.SnippetAttr))
+		if err != nil || i < 0 || i >= len(les.Snippets) {
+			continue
+		}
+		ui.Listener("click", func(dom.Event) {
+			tt.page.LoadSnippet(les.Snippets[i])
+		}).Apply(button)
+	}
 }
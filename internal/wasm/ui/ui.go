@@ -77,6 +77,7 @@ func (ui *UI) CreateParagraph(parent dom.Element, text string, opts ...ElementOp
 	el := ui.win.Document().CreateElement("p")
 	parent.AppendChild(el)
 	el.SetInnerHTML(html.EscapeString(text))
+	applyAll(el, opts)
 	return el.(*dom.HTMLParagraphElement)
 }
 
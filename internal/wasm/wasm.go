@@ -37,28 +37,54 @@ type root struct {
 func (r *root) DisplayLesson(les *lessons.Lesson) {
 	r.text.SetContent(les)
 	r.code.SetContent(les)
-	r.gui.UpdateURL(fmt.Sprintf("index.html?chapter=%d&lesson=%d", les.Chapter.ID, les.ID))
+	r.gui.UpdateURL("index.html?lesson=" + les.Slug)
 }
 
-func idsFromURL(loc *url.URL) (int, int) {
-	chapS := loc.Query().Get("chapter")
-	if chapS == "" {
-		return 0, 0
-	}
-	chapID, err := strconv.Atoi(chapS)
+// LoadSnippet replaces the source of the file displayed in the editor with a snippet of the text.
+func (r *root) LoadSnippet(src string) {
+	r.code.LoadSnippet(src)
+}
+
+// displaySnippet displays the lesson of a shared snippet with its code in the editor.
+func (r *root) displaySnippet(chapters []*lessons.Chapter, id string) {
+	snip, err := code.FetchSnippet(r.gui, id)
 	if err != nil {
-		fmt.Printf("ERROR: cannot parse chapter ID %q: %v\n", chapS, err)
+		fmt.Println("ERROR:", err.Error())
+		r.DisplayLesson(lessons.FindLesson(chapters, 0, 0))
+		return
 	}
-	lesS := loc.Query().Get("lesson")
-	if lesS == "" {
-		return chapID, 0
+	les := lessons.BySlug(chapters, snip.Slug)
+	if les == nil {
+		les = lessons.FindLesson(chapters, snip.Chapter, snip.Lesson)
 	}
+	r.DisplayLesson(les)
+	r.code.SetSource(snip.Source)
+}
 
-	lesID, err := strconv.Atoi(chapS)
+func parseID(kind, s string) int {
+	if s == "" {
+		return 0
+	}
+	id, err := strconv.Atoi(s)
 	if err != nil {
-		fmt.Printf("ERROR: cannot parse lessons ID %q: %v\n", lesS, err)
+		fmt.Printf("ERROR: cannot parse %s ID %q: %v\n", kind, s, err)
+	}
+	return id
+}
+
+// lessonFromURL returns the lesson referred to by the query of the page URL,
+// either by its slug or by its chapter and lesson numbers for old URLs.
+// It returns nil if the URL does not refer to a lesson.
+func lessonFromURL(chapters []*lessons.Chapter, query url.Values) *lessons.Lesson {
+	if query.Has("chapter") {
+		return lessons.FindLesson(chapters, parseID("chapter", query.Get("chapter")), parseID("lesson", query.Get("lesson")))
 	}
-	return lesID, chapID
+	return lessons.BySlug(chapters, query.Get("lesson"))
+}
+
+// lessonFromPage returns the lesson whose slug is stored in the body of pre-rendered lesson pages.
+func lessonFromPage(chapters []*lessons.Chapter, body dom.HTMLElement) *lessons.Lesson {
+	return lessons.BySlug(chapters, body.GetAttribute("data-lesson"))
 }
 
 func main() {
@@ -79,13 +105,23 @@ func main() {
 		return
 	}
 	loc, err := gui.URL()
-	var chapID, lessonID int
+	var les *lessons.Lesson
 	if err != nil {
 		fmt.Println("URL ERROR", err.Error())
 	} else {
-		chapID, lessonID = idsFromURL(loc)
+		les = lessonFromURL(chapters, loc.Query())
+	}
+	if les == nil {
+		les = lessonFromPage(chapters, body)
+	}
+	if les == nil {
+		les = lessons.FindLesson(chapters, 0, 0)
+	}
+	if loc != nil && loc.Query().Get("share") != "" {
+		root.displaySnippet(chapters, loc.Query().Get("share"))
+	} else {
+		root.DisplayLesson(les)
 	}
-	root.DisplayLesson(lessons.FindLesson(chapters, chapID, lessonID))
 
 	<-make(chan bool)
 }
@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wasmbuild compiles the WASM binary running the overview in the browser.
+package wasmbuild
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// Package is the Go package compiled to WASM, relative to the module root.
+	Package = "./internal/wasm"
+
+	lessonsFolder = "lessons"
+)
+
+// Build compiles the WASM binary from the module rooted at moduleRoot
+// and writes it to out.
+func Build(moduleRoot, out string) error {
+	out, err := filepath.Abs(out)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("go", "build", "-o", out, Package)
+	cmd.Dir = moduleRoot
+	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cannot build %s:\n%s%v", Package, output, err)
+	}
+	return nil
+}
+
+// SourceHash returns a hash of all the files in the module rooted at moduleRoot
+// from which the WASM binary is built, that is Go sources, go.mod, go.sum,
+// and all the files in the lessons folder.
+func SourceHash(moduleRoot string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(moduleRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(moduleRoot, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if rel != "." && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isSource(rel) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(rel), len(data))
+		h.Write(data)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func isSource(rel string) bool {
+	switch {
+	case rel == "go.mod" || rel == "go.sum":
+		return true
+	case filepath.Ext(rel) == ".go":
+		return true
+	case strings.HasPrefix(filepath.ToSlash(rel), lessonsFolder+"/"):
+		return true
+	}
+	return false
+}
+
+type (
+	// Builder builds the WASM binary only when its sources have changed.
+	// Concurrent calls to Build share the same build.
+	Builder struct {
+		moduleRoot string
+		out        string
+
+		mu      sync.Mutex
+		current *build
+
+		// hash of the sources of the last successful build.
+		// Only accessed by the running build.
+		hash string
+	}
+
+	build struct {
+		done chan struct{}
+		err  error
+	}
+)
+
+// NewBuilder returns a builder compiling the module rooted at moduleRoot
+// into out.
+func NewBuilder(moduleRoot, out string) *Builder {
+	return &Builder{moduleRoot: moduleRoot, out: out}
+}
+
+// Build the WASM binary if its sources have changed since the last successful build.
+// If a build is already running, Build waits for it and returns its result.
+func (b *Builder) Build() error {
+	b.mu.Lock()
+	if bld := b.current; bld != nil {
+		b.mu.Unlock()
+		<-bld.done
+		return bld.err
+	}
+	bld := &build{done: make(chan struct{})}
+	b.current = bld
+	b.mu.Unlock()
+
+	bld.err = b.buildIfChanged()
+
+	b.mu.Lock()
+	b.current = nil
+	b.mu.Unlock()
+	close(bld.done)
+	return bld.err
+}
+
+func (b *Builder) buildIfChanged() error {
+	hash, err := SourceHash(b.moduleRoot)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(b.out); hash == b.hash && err == nil {
+		return nil
+	}
+	if err := Build(b.moduleRoot, b.out); err != nil {
+		return err
+	}
+	b.hash = hash
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wasmbuild_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gx-org/gx-org/internal/wasmbuild"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newModule(t *testing.T) string {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":                "module example.com/overview\n\ngo 1.24\n",
+		"internal/wasm/wasm.go": "package main\n\nfunc main() {}\n",
+		"lessons/1_1.md":        "# Title\n",
+		"res/style.css":         "body {}\n",
+	})
+	return root
+}
+
+func sourceHash(t *testing.T, root string) string {
+	hash, err := wasmbuild.SourceHash(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func TestSourceHash(t *testing.T) {
+	root := newModule(t)
+	tests := []struct {
+		file    string
+		changed bool
+	}{
+		{file: "res/style.css", changed: false},
+		{file: ".git/HEAD", changed: false},
+		{file: "README.md", changed: false},
+		{file: "lessons/1_1.md", changed: true},
+		{file: "lessons/1_2.md", changed: true},
+		{file: "internal/wasm/wasm.go", changed: true},
+		{file: "internal/other/other.go", changed: true},
+		{file: "go.sum", changed: true},
+	}
+	for i, test := range tests {
+		before := sourceHash(t, root)
+		writeFiles(t, root, map[string]string{test.file: "changed " + test.file})
+		after := sourceHash(t, root)
+		if changed := before != after; changed != test.changed {
+			t.Errorf("test %d: changing %s: got changed=%v but want %v", i, test.file, changed, test.changed)
+		}
+	}
+}
+
+func modTime(t *testing.T, path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.ModTime()
+}
+
+func TestBuilder(t *testing.T) {
+	root := newModule(t)
+	out := filepath.Join(root, "res", "main.wasm")
+	bld := wasmbuild.NewBuilder(root, out)
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = bld.Build()
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("build %d: %v", i, err)
+		}
+	}
+	first := modTime(t, out)
+
+	if err := bld.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if got := modTime(t, out); !got.Equal(first) {
+		t.Errorf("WASM binary rebuilt without source changes")
+	}
+
+	writeFiles(t, root, map[string]string{"lessons/1_1.md": "# New title\n"})
+	time.Sleep(10 * time.Millisecond)
+	if err := bld.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if got := modTime(t, out); got.Equal(first) {
+		t.Errorf("WASM binary not rebuilt after a lesson changed")
+	}
+}
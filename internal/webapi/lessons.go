@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webapi
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gx-org/gx-org/internal/assets"
+	"github.com/gx-org/gx-org/internal/lessons"
+	"github.com/gx-org/gx-org/internal/mdtext"
+)
+
+type (
+	// LessonRef references a lesson.
+	LessonRef struct {
+		Chapter int    `json:"chapter"`
+		Lesson  int    `json:"lesson"`
+		Slug    string `json:"slug"`
+	}
+
+	// ChapterTOC is a chapter in the table of contents.
+	ChapterTOC struct {
+		ID      int         `json:"id"`
+		Title   string      `json:"title"`
+		Lessons []LessonRef `json:"lessons"`
+	}
+
+	// TOC is the table of contents of the course.
+	TOC struct {
+		Chapters []ChapterTOC `json:"chapters"`
+	}
+
+	// Lesson is the content of a lesson.
+	Lesson struct {
+		LessonRef
+		Title string             `json:"title"`
+		Meta  mdtext.FrontMatter `json:"meta"`
+		HTML  string             `json:"html"`
+		Files []SourceFile       `json:"files"`
+		Prev  *LessonRef         `json:"prev"`
+		Next  *LessonRef         `json:"next"`
+	}
+
+	// SourceFile is a GX source file of a lesson.
+	SourceFile struct {
+		Name   string `json:"name"`
+		Source string `json:"source"`
+	}
+
+	// Lessons serves the content of the course.
+	Lessons struct {
+		chapters []*lessons.Chapter
+	}
+)
+
+// NewLessons returns the handlers serving the content of the course.
+func NewLessons(chapters []*lessons.Chapter) *Lessons {
+	return &Lessons{chapters: chapters}
+}
+
+// Route registers the lessons handlers in a router.
+func (l *Lessons) Route(r chi.Router) {
+	r.Get("/", l.toc)
+	r.Get("/{slug}", l.lessonBySlug)
+	r.Get("/{chapter}/{lesson}", l.lesson)
+}
+
+// RedirectNumeric is a middleware redirecting the URLs of the overview page
+// referring to a lesson by its chapter and lesson numbers (e.g. ?chapter=2&lesson=1)
+// to the URL referring to the lesson by its slug (e.g. ?lesson=builtin-types).
+func (l *Lessons) RedirectNumeric(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" && path.Base(r.URL.Path) != assets.IndexFile {
+			next.ServeHTTP(w, r)
+			return
+		}
+		query := r.URL.Query()
+		chapID, chapErr := strconv.Atoi(query.Get("chapter"))
+		lessonID, lessonErr := strconv.Atoi(query.Get("lesson"))
+		if chapErr != nil || lessonErr != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		les := lessons.Lookup(l.chapters, chapID, lessonID)
+		if les == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		query.Del("chapter")
+		query.Set("lesson", les.Slug)
+		target := *r.URL
+		target.RawQuery = query.Encode()
+		http.Redirect(w, r, target.String(), http.StatusMovedPermanently)
+	})
+}
+
+func refOf(les *lessons.Lesson) *LessonRef {
+	if les == nil {
+		return nil
+	}
+	return &LessonRef{Chapter: les.Chapter.ID, Lesson: les.ID, Slug: les.Slug}
+}
+
+func (l *Lessons) toc(w http.ResponseWriter, r *http.Request) {
+	toc := TOC{Chapters: []ChapterTOC{}}
+	for _, chap := range l.chapters {
+		chapTOC := ChapterTOC{ID: chap.ID, Title: chap.Title}
+		for _, les := range chap.Content {
+			chapTOC.Lessons = append(chapTOC.Lessons, *refOf(les))
+		}
+		toc.Chapters = append(toc.Chapters, chapTOC)
+	}
+	writeJSON(w, http.StatusOK, toc)
+}
+
+func (l *Lessons) lesson(w http.ResponseWriter, r *http.Request) {
+	chapS, lessonS := chi.URLParam(r, "chapter"), chi.URLParam(r, "lesson")
+	chapID, chapErr := strconv.Atoi(chapS)
+	lessonID, lessonErr := strconv.Atoi(lessonS)
+	var les *lessons.Lesson
+	if chapErr == nil && lessonErr == nil {
+		les = lessons.Lookup(l.chapters, chapID, lessonID)
+	}
+	if les == nil {
+		writeError(w, http.StatusNotFound, "lesson %s/%s not found", chapS, lessonS)
+		return
+	}
+	writeLesson(w, les)
+}
+
+func (l *Lessons) lessonBySlug(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	les := lessons.BySlug(l.chapters, slug)
+	if les == nil {
+		writeError(w, http.StatusNotFound, "lesson %s not found", slug)
+		return
+	}
+	writeLesson(w, les)
+}
+
+func sourceFiles(files []lessons.SourceFile) []SourceFile {
+	srcs := make([]SourceFile, len(files))
+	for i, file := range files {
+		srcs[i] = SourceFile(file)
+	}
+	return srcs
+}
+
+func writeLesson(w http.ResponseWriter, les *lessons.Lesson) {
+	writeJSON(w, http.StatusOK, Lesson{
+		LessonRef: *refOf(les),
+		Title:     les.Title,
+		Meta:      les.Meta,
+		HTML:      les.HTML,
+		Files:     sourceFiles(les.Sources),
+		Prev:      refOf(les.Prev),
+		Next:      refOf(les.Next),
+	})
+}
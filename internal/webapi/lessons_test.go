@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webapi_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gx-org/gx-org/internal/lessons"
+	"github.com/gx-org/gx-org/internal/webapi"
+)
+
+func TestLessons(t *testing.T) {
+	chapters, err := lessons.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := chi.NewRouter()
+	r.Route("/api/lessons", webapi.NewLessons(chapters).Route)
+
+	var toc webapi.TOC
+	do(t, r, http.MethodGet, "/api/lessons", "", http.StatusOK, &toc)
+	if len(toc.Chapters) != len(chapters) {
+		t.Fatalf("got %d chapters but want %d", len(toc.Chapters), len(chapters))
+	}
+	var prev *webapi.LessonRef
+	for _, chap := range toc.Chapters {
+		if chap.Title == "" {
+			t.Errorf("chapter %d has no title", chap.ID)
+		}
+		titles := make(map[string]bool)
+		for _, ref := range chap.Lessons {
+			var les webapi.Lesson
+			do(t, r, http.MethodGet, fmt.Sprintf("/api/lessons/%d/%d", ref.Chapter, ref.Lesson), "", http.StatusOK, &les)
+			if les.LessonRef != ref {
+				t.Errorf("got lesson %v but want %v", les.LessonRef, ref)
+			}
+			if les.Title == "" || titles[les.Title] {
+				t.Errorf("lesson %v: title %q is empty or used by another lesson of the chapter", ref, les.Title)
+			}
+			titles[les.Title] = true
+			var bySlug webapi.Lesson
+			do(t, r, http.MethodGet, "/api/lessons/"+ref.Slug, "", http.StatusOK, &bySlug)
+			if bySlug.LessonRef != ref {
+				t.Errorf("got lesson %v for slug %q but want %v", bySlug.LessonRef, ref.Slug, ref)
+			}
+			if les.HTML == "" || len(les.Files) == 0 || les.Files[0].Source == "" {
+				t.Errorf("lesson %v has no content", ref)
+			}
+			if (prev == nil) != (les.Prev == nil) || (prev != nil && *prev != *les.Prev) {
+				t.Errorf("lesson %v: got previous lesson %v but want %v", ref, les.Prev, prev)
+			}
+			prev = &ref
+		}
+	}
+	for _, target := range []string{"/api/lessons/0/1", "/api/lessons/1/100", "/api/lessons/a/b", "/api/lessons/unknown"} {
+		do(t, r, http.MethodGet, target, "", http.StatusNotFound, nil)
+	}
+}
+
+func TestRedirectNumeric(t *testing.T) {
+	chapters, err := lessons.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	les := chapters[len(chapters)-1].Content[0]
+	h := webapi.NewLessons(chapters).RedirectNumeric(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	tests := []struct {
+		target   string
+		location string
+	}{
+		{
+			target:   fmt.Sprintf("/?chapter=%d&lesson=%d", les.Chapter.ID, les.ID),
+			location: "/?lesson=" + les.Slug,
+		},
+		{
+			target:   fmt.Sprintf("/index.html?chapter=%d&lesson=%d&share=abc", les.Chapter.ID, les.ID),
+			location: "/index.html?lesson=" + les.Slug + "&share=abc",
+		},
+		{target: "/?lesson=" + les.Slug},
+		{target: "/?chapter=100&lesson=1"},
+		{target: fmt.Sprintf("/res/style.css?chapter=%d&lesson=%d", les.Chapter.ID, les.ID)},
+	}
+	for i, test := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.target, nil))
+		if got := rec.Header().Get("Location"); got != test.location {
+			t.Errorf("test %d: %s redirected to %q but want %q", i, test.target, got, test.location)
+		}
+	}
+}
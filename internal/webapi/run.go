@@ -0,0 +1,238 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/gx-org/gx-org/internal/diag"
+)
+
+const (
+	// WorkerEnv is the environment variable set to the task of a worker when starting it.
+	WorkerEnv = "GXORG_RUN_WORKER"
+	// TaskRun is the task of a worker compiling and running code.
+	TaskRun = "run"
+	// TaskCheck is the task of a worker compiling code to report diagnostics.
+	TaskCheck = "check"
+	// WorkerMemoryEnv is the environment variable with the memory limit of a worker in bytes.
+	WorkerMemoryEnv = "GXORG_RUN_WORKER_MEMORY"
+	// ExitMemory is the exit code of a worker exceeding its memory limit.
+	ExitMemory = 3
+)
+
+type (
+	// RunRequest is the body of a POST request to the run endpoint.
+	// Files are compiled in order into the same package.
+	RunRequest struct {
+		Files []SourceFile `json:"files"`
+	}
+
+	// FuncResult is the result of calling a GX function.
+	FuncResult struct {
+		Name       string  `json:"name"`
+		Output     string  `json:"output,omitempty"`
+		Error      string  `json:"error,omitempty"`
+		DurationMS float64 `json:"duration_ms"`
+	}
+
+	// RunResponse is the body of the response of the run endpoint.
+	// Error is set if the source cannot be compiled or if a limit has been exceeded.
+	RunResponse struct {
+		Error     string       `json:"error,omitempty"`
+		CompileMS float64      `json:"compile_ms"`
+		Funcs     []FuncResult `json:"funcs"`
+	}
+
+	// CheckRequest is the body of a POST request to the check endpoint.
+	// Files are compiled in order into the same package.
+	CheckRequest struct {
+		Files []SourceFile `json:"files"`
+	}
+
+	// CheckResponse is the body of the response of the check endpoint.
+	// Diagnostics is empty if the source compiles.
+	CheckResponse struct {
+		Diagnostics []diag.Diagnostic `json:"diagnostics"`
+	}
+
+	// RunLimits are the limits enforced when running GX code.
+	RunLimits struct {
+		// MaxSourceBytes is the maximum size of a request.
+		MaxSourceBytes int64
+		// Timeout is the maximum wall-clock time to compile and run the code.
+		// It starts once a worker is available.
+		Timeout time.Duration
+		// QueueTimeout is the maximum time a request waits for a worker to be available.
+		QueueTimeout time.Duration
+		// MaxMemoryBytes is the maximum heap size of a worker.
+		MaxMemoryBytes int64
+		// MaxWorkers is the maximum number of requests processed concurrently.
+		MaxWorkers int
+	}
+
+	// Runner compiles and runs GX code in worker processes.
+	Runner struct {
+		limits  RunLimits
+		exe     string
+		workers chan struct{}
+	}
+)
+
+// DefaultRunLimits returns the limits used by default by the server.
+func DefaultRunLimits() RunLimits {
+	return RunLimits{
+		MaxSourceBytes: 64 << 10,
+		Timeout:        10 * time.Second,
+		QueueTimeout:   5 * time.Second,
+		MaxMemoryBytes: 512 << 20,
+		MaxWorkers:     4,
+	}
+}
+
+// NewRunner returns a handler running GX code.
+// Workers are started by executing the current binary
+// which needs to call worker.Run when worker.IsWorker returns true.
+func NewRunner(limits RunLimits) (*Runner, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the executable to start workers: %v", err)
+	}
+	return &Runner{
+		limits:  limits,
+		exe:     exe,
+		workers: make(chan struct{}, limits.MaxWorkers),
+	}, nil
+}
+
+// DurationMS converts a duration in milliseconds.
+func DurationMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// ServeHTTP compiles and runs the GX source files of a request.
+func (r *Runner) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.serve(w, req, TaskRun, &RunRequest{}, &RunResponse{})
+}
+
+// Check compiles the GX source files of a request and returns their diagnostics.
+func (r *Runner) Check(w http.ResponseWriter, req *http.Request) {
+	r.serve(w, req, TaskCheck, &CheckRequest{}, &CheckResponse{})
+}
+
+// serve decodes a request into workerReq, processes it in a worker running task,
+// and writes workerResp decoded from the output of the worker.
+func (r *Runner) serve(w http.ResponseWriter, req *http.Request, task string, workerReq, workerResp any) {
+	if !readJSON(w, req, r.limits.MaxSourceBytes, workerReq) {
+		return
+	}
+	if !r.acquireWorker(req.Context()) {
+		if req.Context().Err() == nil {
+			writeError(w, http.StatusServiceUnavailable, "server busy: try again later")
+		}
+		return
+	}
+	defer func() { <-r.workers }()
+	ctx, cancel := context.WithTimeout(req.Context(), r.limits.Timeout)
+	defer cancel()
+	if err := r.runWorker(ctx, task, workerReq, workerResp); err != nil {
+		if err.status == 0 {
+			// The client is gone: there is no one to reply to.
+			return
+		}
+		writeError(w, err.status, "%s", err.msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, workerResp)
+}
+
+// acquireWorker waits for a worker to be available for at most QueueTimeout.
+// It returns false if no worker is available in time or if the request is canceled.
+func (r *Runner) acquireWorker(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, r.limits.QueueTimeout)
+	defer cancel()
+	select {
+	case r.workers <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// workerError is an error processing a request in a worker.
+// msg is returned to the client and does not include details of the failure.
+// status is 0 if the request has been canceled by the client.
+type workerError struct {
+	status int
+	msg    string
+}
+
+// internalError logs the details of a failure and returns a generic error.
+func internalError(msg string, args ...any) *workerError {
+	slog.Error(msg, args...)
+	return &workerError{status: http.StatusInternalServerError, msg: "internal error: cannot run the code"}
+}
+
+func (r *Runner) runWorker(ctx context.Context, task string, req, resp any) *workerError {
+	in, err := json.Marshal(req)
+	if err != nil {
+		return internalError("cannot encode worker request", "task", task, "err", err)
+	}
+	var out, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.exe)
+	cmd.Env = append(os.Environ(),
+		WorkerEnv+"="+task,
+		WorkerMemoryEnv+"="+strconv.FormatInt(r.limits.MaxMemoryBytes, 10),
+	)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return &workerError{}
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &workerError{
+			status: http.StatusGatewayTimeout,
+			msg:    fmt.Sprintf("time limit of %s exceeded", r.limits.Timeout),
+		}
+	}
+	if exitErr := (*exec.ExitError)(nil); errors.As(err, &exitErr) && exitErr.ExitCode() == ExitMemory {
+		return &workerError{
+			status: http.StatusUnprocessableEntity,
+			msg:    fmt.Sprintf("memory limit of %d MiB exceeded", r.limits.MaxMemoryBytes>>20),
+		}
+	}
+	if err != nil {
+		return internalError("worker failed", "task", task, "err", err, "stderr", stderr.String())
+	}
+	if stderr.Len() > 0 {
+		slog.Warn("worker wrote to stderr", "task", task, "stderr", stderr.String())
+	}
+	if err := json.Unmarshal(out.Bytes(), resp); err != nil {
+		return internalError("cannot decode worker response", "task", task, "err", err)
+	}
+	return nil
+}
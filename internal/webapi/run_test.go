@@ -0,0 +1,172 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/gx-org/gx-org/internal/webapi"
+)
+
+// Sources processed by the fake worker.
+const (
+	sleepSource = "sleep"
+	crashSource = "crash"
+	crashDetail = "secret worker detail"
+)
+
+// TestMain runs the test binary as a fake worker when it is started by a webapi.Runner.
+func TestMain(m *testing.M) {
+	if os.Getenv(webapi.WorkerEnv) != "" {
+		fakeWorker()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// fakeWorker echoes the files of a run request as the outputs of functions
+// named after the files without compiling them.
+// It sleeps or crashes for the sources above.
+func fakeWorker() {
+	var req webapi.RunRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	resp := webapi.RunResponse{Funcs: []webapi.FuncResult{}}
+	for _, file := range req.Files {
+		switch file.Source {
+		case sleepSource:
+			time.Sleep(time.Minute)
+		case crashSource:
+			fmt.Fprintln(os.Stderr, crashDetail)
+			os.Exit(1)
+		}
+		resp.Funcs = append(resp.Funcs, webapi.FuncResult{Name: file.Name, Output: file.Source})
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		os.Exit(1)
+	}
+}
+
+// runRequest returns the body of a run request with a single main.gx file.
+func runRequest(t *testing.T, source string) string {
+	return runFilesRequest(t, webapi.SourceFile{Name: "main.gx", Source: source})
+}
+
+func runFilesRequest(t *testing.T, files ...webapi.SourceFile) string {
+	body, err := json.Marshal(webapi.RunRequest{Files: files})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func newRunRouter(t *testing.T) http.Handler {
+	runner, err := webapi.NewRunner(webapi.RunLimits{
+		MaxSourceBytes: 256,
+		Timeout:        time.Second,
+		QueueTimeout:   100 * time.Millisecond,
+		MaxMemoryBytes: 512 << 20,
+		MaxWorkers:     1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := chi.NewRouter()
+	r.Post("/api/run", runner.ServeHTTP)
+	return r
+}
+
+func TestRun(t *testing.T) {
+	r := newRunRouter(t)
+
+	var resp webapi.RunResponse
+	do(t, r, http.MethodPost, "/api/run", runRequest(t, "package main\n"), http.StatusOK, &resp)
+	want := webapi.RunResponse{Funcs: []webapi.FuncResult{{Name: "main.gx", Output: "package main\n"}}}
+	if diff := cmp.Diff(want, resp); diff != "" {
+		t.Errorf("unexpected response (-want +got):\n%s", diff)
+	}
+
+	// All the files of a request are passed to the worker in order.
+	files := []webapi.SourceFile{
+		{Name: "main.gx", Source: "package main\n"},
+		{Name: "util.gx", Source: "package main\n\nfunc Util() {}\n"},
+	}
+	do(t, r, http.MethodPost, "/api/run", runFilesRequest(t, files...), http.StatusOK, &resp)
+	want = webapi.RunResponse{Funcs: []webapi.FuncResult{
+		{Name: "main.gx", Output: files[0].Source},
+		{Name: "util.gx", Output: files[1].Source},
+	}}
+	if diff := cmp.Diff(want, resp); diff != "" {
+		t.Errorf("unexpected response for two files (-want +got):\n%s", diff)
+	}
+
+	do(t, r, http.MethodPost, "/api/run", runRequest(t, strings.Repeat("a", 256)), http.StatusRequestEntityTooLarge, nil)
+
+	start := time.Now()
+	var timeout webapi.Error
+	do(t, r, http.MethodPost, "/api/run", runRequest(t, sleepSource), http.StatusGatewayTimeout, &timeout)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("worker stopped after %s but the timeout is 1s", elapsed)
+	}
+	if !strings.Contains(timeout.Error, "time limit") {
+		t.Errorf("unexpected timeout error %q", timeout.Error)
+	}
+
+	var crash webapi.Error
+	do(t, r, http.MethodPost, "/api/run", runRequest(t, crashSource), http.StatusInternalServerError, &crash)
+	if strings.Contains(crash.Error, crashDetail) {
+		t.Errorf("error %q returned to the client contains the worker stderr", crash.Error)
+	}
+}
+
+func TestRunQueue(t *testing.T) {
+	r := newRunRouter(t)
+	done := make(chan int)
+	go func() {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/run", strings.NewReader(runRequest(t, sleepSource))))
+		done <- rec.Code
+	}()
+	// Wait for the sleeping request to hold the only worker.
+	time.Sleep(50 * time.Millisecond)
+	do(t, r, http.MethodPost, "/api/run", runRequest(t, "package main\n"), http.StatusServiceUnavailable, nil)
+	if status := <-done; status != http.StatusGatewayTimeout {
+		t.Errorf("got status %d for the request holding the worker but want %d", status, http.StatusGatewayTimeout)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	r := newRunRouter(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/api/run", strings.NewReader(runRequest(t, sleepSource))).WithContext(ctx)
+	time.AfterFunc(100*time.Millisecond, cancel)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Body.Len() != 0 {
+		t.Errorf("unexpected response to a canceled request: %d %s", rec.Code, rec.Body.String())
+	}
+}
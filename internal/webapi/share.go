@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webapi
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gx-org/gx-org/internal/share"
+)
+
+// ShareResponse is the body of the response when a snippet is shared.
+type ShareResponse struct {
+	ID string `json:"id"`
+}
+
+// Share stores and retrieves snippets shared by users.
+type Share struct {
+	store    share.Store
+	maxBytes int64
+}
+
+// NewShare returns the handlers to share snippets.
+// Requests larger than maxBytes are rejected.
+func NewShare(store share.Store, maxBytes int64) *Share {
+	return &Share{store: store, maxBytes: maxBytes}
+}
+
+// Route registers the share handlers in a router.
+func (s *Share) Route(r chi.Router) {
+	r.Post("/", s.put)
+	r.Get("/{id}", s.get)
+}
+
+func (s *Share) put(w http.ResponseWriter, r *http.Request) {
+	snip := &share.Snippet{}
+	if !readJSON(w, r, s.maxBytes, snip) {
+		return
+	}
+	id, err := s.store.Put(snip)
+	if err != nil {
+		slog.Error("cannot store snippet", "err", err)
+		writeError(w, http.StatusInternalServerError, "internal error: cannot store snippet")
+		return
+	}
+	writeJSON(w, http.StatusOK, ShareResponse{ID: id})
+}
+
+func (s *Share) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	snip, err := s.store.Get(id)
+	if errors.Is(err, share.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+		writeError(w, http.StatusNotFound, "snippet %q not found", id)
+		return
+	}
+	if err != nil {
+		// Errors of the store can include file paths: they are only logged.
+		slog.Error("cannot read snippet", "id", id, "err", err)
+		writeError(w, http.StatusInternalServerError, "internal error: cannot read snippet %q", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, snip)
+}
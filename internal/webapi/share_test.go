@@ -0,0 +1,129 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webapi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/gx-org/gx-org/internal/share"
+	"github.com/gx-org/gx-org/internal/webapi"
+)
+
+func do(t *testing.T, h http.Handler, method, target, body string, wantStatus int, resp any) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != wantStatus {
+		t.Fatalf("%s %s: got status %d but want %d: %s", method, target, rec.Code, wantStatus, rec.Body.String())
+	}
+	if resp == nil {
+		return
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+		t.Fatalf("%s %s: cannot decode response %q: %v", method, target, rec.Body.String(), err)
+	}
+}
+
+func TestShare(t *testing.T) {
+	store, err := share.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := chi.NewRouter()
+	r.Route("/api/share", webapi.NewShare(store, 1024).Route)
+
+	want := share.Snippet{Source: "package main\n", Chapter: 2, Lesson: 1}
+	body, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shared webapi.ShareResponse
+	do(t, r, http.MethodPost, "/api/share", string(body), http.StatusOK, &shared)
+
+	var got share.Snippet
+	do(t, r, http.MethodGet, "/api/share/"+shared.ID, "", http.StatusOK, &got)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected snippet (-want +got):\n%s", diff)
+	}
+
+	do(t, r, http.MethodGet, "/api/share/AAAAAAAAAAAA", "", http.StatusNotFound, nil)
+	do(t, r, http.MethodPost, "/api/share", `{"unknown":1}`, http.StatusBadRequest, nil)
+	do(t, r, http.MethodPost, "/api/share", `{"source":"`+strings.Repeat("a", 2048)+`"}`, http.StatusRequestEntityTooLarge, nil)
+}
+
+// failingStore fails all requests with an error.
+type failingStore struct {
+	err error
+}
+
+func (s failingStore) Put(*share.Snippet) (string, error) {
+	return "", s.err
+}
+
+func (s failingStore) Get(string) (*share.Snippet, error) {
+	return nil, s.err
+}
+
+func TestShareErrors(t *testing.T) {
+	const path = "/var/lib/snippets/AAAAAAAAAAAA.json"
+	tests := []struct {
+		err        error
+		wantStatus int
+	}{
+		{
+			err:        fmt.Errorf("open %s: permission denied", path),
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			err:        &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for i, test := range tests {
+		r := chi.NewRouter()
+		r.Route("/api/share", webapi.NewShare(failingStore{err: test.err}, 1024).Route)
+		for _, req := range []struct{ method, body string }{
+			{method: http.MethodGet},
+			{method: http.MethodPost, body: `{"source":"package main"}`},
+		} {
+			target := "/api/share"
+			if req.method == http.MethodGet {
+				target += "/AAAAAAAAAAAA"
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(req.method, target, strings.NewReader(req.body)))
+			wantStatus := test.wantStatus
+			if req.method == http.MethodPost {
+				// Failing to store a snippet is always an internal error.
+				wantStatus = http.StatusInternalServerError
+			}
+			if rec.Code != wantStatus {
+				t.Errorf("test %d: %s: got status %d but want %d", i, req.method, rec.Code, wantStatus)
+			}
+			if strings.Contains(rec.Body.String(), path) {
+				t.Errorf("test %d: %s: the response leaks the error of the store: %s", i, req.method, rec.Body.String())
+			}
+		}
+	}
+}
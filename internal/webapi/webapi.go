@@ -0,0 +1,59 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webapi implements the JSON API served by the overview server.
+package webapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error is the JSON body returned when a request fails.
+type Error struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Printf("ERROR: cannot write JSON response: %v\n", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, format string, a ...any) {
+	writeJSON(w, status, Error{Error: fmt.Sprintf(format, a...)})
+}
+
+// readJSON decodes the body of a request into v.
+// Bodies larger than maxBytes are rejected.
+// An error has already been written to w when readJSON returns false.
+func readJSON(w http.ResponseWriter, r *http.Request, maxBytes int64, v any) bool {
+	body := http.MaxBytesReader(w, r.Body, maxBytes)
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err == nil {
+		return true
+	}
+	if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+		writeError(w, http.StatusRequestEntityTooLarge, "request larger than %d bytes", maxErr.Limit)
+		return false
+	}
+	writeError(w, http.StatusBadRequest, "cannot decode request: %v", err)
+	return false
+}
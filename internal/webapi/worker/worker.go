@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package worker compiles and runs GX code for the run and check endpoints in a separate process.
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gx-org/gx-org/internal/diag"
+	"github.com/gx-org/gx-org/internal/gxrun"
+	"github.com/gx-org/gx-org/internal/webapi"
+)
+
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// IsWorker returns true if the current process has been started
+// by a webapi.Runner to run GX code.
+func IsWorker() bool {
+	return os.Getenv(webapi.WorkerEnv) != ""
+}
+
+// watchMemory exits the process when the heap grows larger than limit.
+func watchMemory(limit int64) {
+	debug.SetMemoryLimit(limit)
+	sample := []metrics.Sample{{Name: heapMetric}}
+	for range time.Tick(10 * time.Millisecond) {
+		metrics.Read(sample)
+		if int64(sample[0].Value.Uint64()) > limit {
+			os.Exit(webapi.ExitMemory)
+		}
+	}
+}
+
+func gxFiles(files []webapi.SourceFile) []gxrun.File {
+	gxFiles := make([]gxrun.File, len(files))
+	for i, file := range files {
+		gxFiles[i] = gxrun.File(file)
+	}
+	return gxFiles
+}
+
+func run(req *webapi.RunRequest) (resp *webapi.RunResponse) {
+	resp = &webapi.RunResponse{Funcs: []webapi.FuncResult{}}
+	defer func() {
+		if r := recover(); r != nil {
+			// The stack is logged by the server but not returned to the client.
+			fmt.Fprintf(os.Stderr, "GX PANIC: %v\n%s", r, debug.Stack())
+			resp.Error = fmt.Sprintf("GX PANIC: %v", r)
+		}
+	}()
+	runner := gxrun.New()
+	start := time.Now()
+	pkg, err := runner.Compile(gxFiles(req.Files)...)
+	resp.CompileMS = webapi.DurationMS(time.Since(start))
+	if err != nil {
+		resp.Error = err.Error()
+		return resp
+	}
+	for _, res := range runner.Run(pkg) {
+		fr := webapi.FuncResult{
+			Name:       res.Name,
+			Output:     res.Output,
+			DurationMS: webapi.DurationMS(res.Duration),
+		}
+		if res.Err != nil {
+			fr.Error = res.Err.Error()
+		}
+		resp.Funcs = append(resp.Funcs, fr)
+	}
+	return resp
+}
+
+func check(req *webapi.CheckRequest) (resp *webapi.CheckResponse) {
+	resp = &webapi.CheckResponse{Diagnostics: []diag.Diagnostic{}}
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "GX PANIC: %v\n%s", r, debug.Stack())
+			resp.Diagnostics = append(resp.Diagnostics, diag.Diagnostic{
+				Severity: diag.SeverityError,
+				Message:  fmt.Sprintf("GX PANIC: %v", r),
+			})
+		}
+	}()
+	srcs := make(map[string]string)
+	for _, file := range req.Files {
+		srcs[file.Name] = file.Source
+	}
+	_, err := gxrun.New().Compile(gxFiles(req.Files)...)
+	resp.Diagnostics = append(resp.Diagnostics, diag.FromError(err, srcs)...)
+	return resp
+}
+
+func decode(v any) error {
+	if err := json.NewDecoder(os.Stdin).Decode(v); err != nil {
+		return fmt.Errorf("cannot decode request: %v", err)
+	}
+	return nil
+}
+
+// Run reads a request from stdin, processes it according to the task
+// of the worker, and writes the response to stdout.
+func Run() error {
+	if limit, err := strconv.ParseInt(os.Getenv(webapi.WorkerMemoryEnv), 10, 64); err == nil && limit > 0 {
+		go watchMemory(limit)
+	}
+	var resp any
+	switch task := os.Getenv(webapi.WorkerEnv); task {
+	case webapi.TaskRun:
+		var req webapi.RunRequest
+		if err := decode(&req); err != nil {
+			return err
+		}
+		resp = run(&req)
+	case webapi.TaskCheck:
+		var req webapi.CheckRequest
+		if err := decode(&req); err != nil {
+			return err
+		}
+		resp = check(&req)
+	default:
+		return fmt.Errorf("unknown worker task %q", task)
+	}
+	return json.NewEncoder(os.Stdout).Encode(resp)
+}
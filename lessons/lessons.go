@@ -2,5 +2,5 @@ package lessons
 
 import "embed"
 
-//go:embed *.md
+//go:embed *.md course.yaml packages
 var Lessons embed.FS
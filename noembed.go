@@ -0,0 +1,33 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !embed
+
+// Package overview gives access to the static files of the overview
+// (index.html and the res folder).
+//
+// The files are only embedded when building with the embed tag.
+// The WASM binary needs to be built first, for example to build
+// a self-contained server:
+//
+//	go generate ./...
+//	go build -tags embed -o gx-overview ./internal
+package overview
+
+import "io/fs"
+
+// Files returns nil: the binary has been built without the embed tag.
+func Files() fs.FS {
+	return nil
+}
@@ -23,3 +23,29 @@ async function runWASM(buffer) {
   go.run(result.instance);
 }
 
+async function loadWASM(wasmURL) {
+  return await (await fetch(wasmURL)).arrayBuffer();
+}
+
+// watchReload reloads the page when the server sends a reload event.
+// Pages served without a reload endpoint (e.g. static hosting) are left alone:
+// the browser closes the connection when the endpoint does not exist.
+function watchReload(url) {
+  if (typeof EventSource === "undefined") {
+    return;
+  }
+  const events = new EventSource(url);
+  events.addEventListener("reload", function() {
+    events.close();
+    location.reload();
+  });
+}
+
+// The script tag loading this file specifies the URL of the WASM binary
+// and of the reload endpoint, so that no inline script is required.
+(function(script) {
+  loadWASM(script.dataset.wasm).then(runWASM);
+  if (script.dataset.reload) {
+    watchReload(script.dataset.reload);
+  }
+})(document.currentScript);